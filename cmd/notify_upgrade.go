@@ -0,0 +1,90 @@
+package main
+
+import (
+	c "dns-monitor/internal/common"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var notifyUpgradeCmd = &cobra.Command{
+	Use:   "notify-upgrade",
+	Short: "Print a config file equivalent to the legacy NOTIFIER_TYPE environment variables",
+	RunE:  runNotifyUpgrade,
+}
+
+// runNotifyUpgrade reads the legacy single-provider environment variables and
+// emits an equivalent declarative config file to stdout, to ease migration to
+// the notifiers: list.
+func runNotifyUpgrade(cmd *cobra.Command, args []string) error {
+	block, err := legacyNotifierBlock(os.Getenv("NOTIFIER_TYPE"))
+	if err != nil {
+		return err
+	}
+
+	config := map[string]any{
+		"domain":                os.Getenv("DOMAIN"),
+		"dns_server":            os.Getenv("DNS_SERVER"),
+		"check_interval":        os.Getenv("CHECK_INTERVAL"),
+		"notify_on_errors":      os.Getenv("NOTIFY_ON_ERRORS") == "true",
+		"custom_subdomains":     splitNonEmpty(os.Getenv("CUSTOM_SUBDOMAINS")),
+		"custom_dkim_selectors": splitNonEmpty(os.Getenv("CUSTOM_DKIM_SELECTORS")),
+		"notifiers":             []map[string]any{block},
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to render config: %w", err)
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), string(out))
+	return nil
+}
+
+func legacyNotifierBlock(notifierType string) (map[string]any, error) {
+	switch notifierType {
+	case c.NotifierTypePushover:
+		return map[string]any{
+			"type":  c.NotifierTypePushover,
+			"token": os.Getenv(c.PushoverAppTokenEnv),
+			"user":  os.Getenv(c.PushoverUserKeyEnv),
+		}, nil
+	case c.NotifierTypeTelegram:
+		return map[string]any{
+			"type":      c.NotifierTypeTelegram,
+			"bot_token": os.Getenv(c.TelegramBotTokenEnv),
+			"chats":     splitInt64(os.Getenv(c.TelegramChatIDsEnv)),
+		}, nil
+	case "":
+		return nil, fmt.Errorf("NOTIFIER_TYPE is not set; nothing to migrate")
+	default:
+		return nil, fmt.Errorf("unsupported legacy notifier type: %s (webhook config isn't representable in the notifiers list yet)", notifierType)
+	}
+}
+
+func splitNonEmpty(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var values []string
+	for _, entry := range strings.Split(raw, ",") {
+		if trimmed := strings.TrimSpace(entry); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+func splitInt64(raw string) []int64 {
+	var values []int64
+	for _, entry := range splitNonEmpty(raw) {
+		if parsed, err := strconv.ParseInt(entry, 10, 64); err == nil {
+			values = append(values, parsed)
+		}
+	}
+	return values
+}
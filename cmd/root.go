@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"dns-monitor/internal/common"
+	"dns-monitor/internal/dns"
+	"dns-monitor/internal/httpapi"
+	"dns-monitor/internal/metrics"
+	"dns-monitor/internal/notification"
+	"dns-monitor/internal/storage"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+var cfgFile string
+
+var rootCmd = &cobra.Command{
+	Use:   "dns-monitor",
+	Short: "Monitor DNS records for unauthorized changes",
+	RunE:  runMonitor,
+}
+
+func init() {
+	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default ./config.yaml or /etc/dns-monitor/config.yaml)")
+	rootCmd.PersistentFlags().String("domain", "", "domain to monitor")
+	rootCmd.PersistentFlags().String("dns-server", "", "DNS server to query")
+	rootCmd.PersistentFlags().String("dns-protocol", "", "DNS transport protocol: udp, tcp, tls, or https")
+	rootCmd.PersistentFlags().Duration("check-interval", 0, "interval between checks")
+	rootCmd.PersistentFlags().Bool("notify-on-errors", false, "send notifications on internal errors")
+	rootCmd.PersistentFlags().String("http-listen", "", "address for the embedded HTTP API")
+	rootCmd.PersistentFlags().String("metrics-addr", "", "address for the Prometheus /metrics endpoint (disabled if unset)")
+	rootCmd.PersistentFlags().String("expected-state-file", "", "path to a desired-state zone file or YAML baseline to diff against (disabled if unset)")
+	rootCmd.PersistentFlags().Bool("dnssec-enabled", false, "request and validate DNSSEC records (RRSIG, DNSKEY, DS, NSEC/NSEC3)")
+	rootCmd.PersistentFlags().Duration("dnssec-expiry-window", 0, "report RRSIGs expiring within this window (disabled if unset)")
+	rootCmd.PersistentFlags().StringSlice("propagation-resolvers", nil, "resolver addresses to confirm changes have propagated to before notifying (disabled if unset)")
+	rootCmd.PersistentFlags().Duration("propagation-timeout", 10*time.Minute, "how long to wait for propagation_resolvers to agree before reporting partial propagation")
+	rootCmd.PersistentFlags().Duration("propagation-interval", 30*time.Second, "delay between propagation_resolvers polling rounds")
+	rootCmd.PersistentFlags().StringSlice("ignored-names", nil, "glob patterns (path.Match syntax) on record name to drop before diffing, e.g. *.dyn.example.com")
+	rootCmd.PersistentFlags().StringSlice("ignored-targets", nil, "glob patterns (path.Match syntax) on record value to drop before diffing, e.g. *.cloudfront.net")
+	rootCmd.PersistentFlags().StringSlice("ignored-types", nil, "record types to drop before diffing, e.g. TXT")
+	rootCmd.PersistentFlags().Bool("dry-run-filter", false, "log which records ignored_names/ignored_targets/ignored_types would drop instead of dropping them")
+
+	bindPFlag("domain", rootCmd.PersistentFlags().Lookup("domain"))
+	bindPFlag("dns_server", rootCmd.PersistentFlags().Lookup("dns-server"))
+	bindPFlag("dns_protocol", rootCmd.PersistentFlags().Lookup("dns-protocol"))
+	bindPFlag("check_interval", rootCmd.PersistentFlags().Lookup("check-interval"))
+	bindPFlag("notify_on_errors", rootCmd.PersistentFlags().Lookup("notify-on-errors"))
+	bindPFlag("http_listen", rootCmd.PersistentFlags().Lookup("http-listen"))
+	bindPFlag("metrics_addr", rootCmd.PersistentFlags().Lookup("metrics-addr"))
+	bindPFlag("expected_state_file", rootCmd.PersistentFlags().Lookup("expected-state-file"))
+	bindPFlag("dnssec_enabled", rootCmd.PersistentFlags().Lookup("dnssec-enabled"))
+	bindPFlag("dnssec_expiry_window", rootCmd.PersistentFlags().Lookup("dnssec-expiry-window"))
+	bindPFlag("propagation_resolvers", rootCmd.PersistentFlags().Lookup("propagation-resolvers"))
+	bindPFlag("propagation_timeout", rootCmd.PersistentFlags().Lookup("propagation-timeout"))
+	bindPFlag("propagation_interval", rootCmd.PersistentFlags().Lookup("propagation-interval"))
+	bindPFlag("ignored_names", rootCmd.PersistentFlags().Lookup("ignored-names"))
+	bindPFlag("ignored_targets", rootCmd.PersistentFlags().Lookup("ignored-targets"))
+	bindPFlag("ignored_types", rootCmd.PersistentFlags().Lookup("ignored-types"))
+	bindPFlag("dry_run_filter", rootCmd.PersistentFlags().Lookup("dry-run-filter"))
+
+	rootCmd.AddCommand(notifyUpgradeCmd)
+}
+
+func bindPFlag(key string, flag *pflag.Flag) {
+	if err := viper.BindPFlag(key, flag); err != nil {
+		log.Fatalf("Failed to bind flag %s: %v", key, err)
+	}
+}
+
+// initConfig wires up the layered configuration: a config file, then
+// environment variables (current behavior, kept for backward compatibility),
+// then CLI flags, in viper's usual precedence order.
+func initConfig() {
+	if cfgFile != "" {
+		viper.SetConfigFile(cfgFile)
+	} else {
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("/etc/dns-monitor")
+		viper.SetConfigName("config")
+	}
+
+	viper.AutomaticEnv()
+
+	if err := viper.ReadInConfig(); err == nil {
+		log.Printf("Using config file: %s", viper.ConfigFileUsed())
+	}
+}
+
+// Execute runs the dns-monitor CLI.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func runMonitor(cmd *cobra.Command, args []string) error {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	log.Println("Starting DNS monitor service")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := common.LoadConfigFromViper(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	factory := notification.NewFactory(config)
+	notifier, err := factory.CreateNotifier()
+	if err != nil {
+		return fmt.Errorf("failed to create notifier: %w", err)
+	}
+	log.Printf("🔔 Notifier %s created successfully 🔔", config.NotificationConfig.NotifierType)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	domains := config.Domains
+	if len(domains) == 0 {
+		domains = []common.DomainConfig{config.PrimaryDomainConfig()}
+	}
+	log.Printf("🌍 Monitoring %d domain(s): %v 🌍", len(domains), domainNames(domains))
+
+	var expected []common.DNSRecord
+	if config.ExpectedStateFile != "" {
+		expected, err = dns.LoadExpectedState(config.ExpectedStateFile)
+		if err != nil {
+			return fmt.Errorf("failed to load expected state: %w", err)
+		}
+		log.Printf("📐 Desired-state mode enabled: %d expected record(s) from %s 📐", len(expected), config.ExpectedStateFile)
+	}
+
+	// mtr is shared by every domain's controller, which distinguish
+	// themselves via the "domain" label on each metric.
+	var mtr *metrics.Metrics
+	var metricsServer *http.Server
+	if config.MetricsAddr != "" {
+		mtr = metrics.New()
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", mtr.Handler())
+		metricsServer = &http.Server{Addr: config.MetricsAddr, Handler: mux}
+		go func() {
+			log.Printf("📊 Metrics listening on %s 📊", config.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Metrics server stopped: %v", err)
+			}
+		}()
+	}
+
+	// The embedded HTTP API and heartbeat are wired to the first domain
+	// only; additional domains in a portfolio are monitored headlessly.
+	var apiServer *httpapi.Server
+	for i, domainCfg := range domains {
+		domainConfig := config.ForDomain(domainCfg)
+
+		prevState, err := storage.LoadPreviousState(domainConfig.Domain)
+		if err != nil {
+			log.Printf("Warning: Could not load previous state for %s: %v", domainConfig.Domain, err)
+			prevState = common.PreviousState{Records: []common.DNSRecord{}}
+
+			if domainConfig.NotifyOnErrors {
+				message := fmt.Sprintf("❌ DNS Monitor Error: Failed to load previous state for %s\n\nError details: %v\n\nTime: %s ❌",
+					domainConfig.Domain, err, time.Now().Format(time.RFC1123))
+				if notifyErr := notifier.SendNotification(ctx, "DNS Monitor Error", message); notifyErr != nil {
+					log.Printf("Failed to send error notification: %v", notifyErr)
+				}
+			}
+		}
+
+		controller := httpapi.NewController(domainConfig, notifier, prevState)
+		controller.SetMetrics(mtr)
+		controller.SetExpectedState(expected)
+
+		if err := controller.SendStartupNotification(ctx); err != nil {
+			log.Printf("Failed to send startup notification for %s: %v", domainConfig.Domain, err)
+		}
+
+		if i == 0 {
+			apiServer = httpapi.NewServer(config.HTTPListenAddr, controller)
+			go func() {
+				log.Printf("🌐 HTTP API listening on %s 🌐", config.HTTPListenAddr)
+				if err := apiServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Printf("HTTP API server stopped: %v", err)
+				}
+			}()
+
+			go controller.RunHeartbeat(ctx)
+		}
+
+		go monitorDomain(ctx, controller, domainCfg.CheckInterval)
+	}
+
+	<-stop
+	log.Println("Received shutdown signal, exiting gracefully")
+	cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if apiServer != nil {
+		if err := apiServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down HTTP API cleanly: %v", err)
+		}
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down metrics server cleanly: %v", err)
+		}
+	}
+	return nil
+}
+
+// monitorDomain runs an initial check against controller and then polls it
+// every interval until ctx is cancelled. Each monitored domain runs its own
+// instance of this loop in its own goroutine, so a slow or misconfigured
+// domain can't delay the others.
+func monitorDomain(ctx context.Context, controller *httpapi.Controller, interval time.Duration) {
+	if _, err := controller.PerformCheck(ctx); err != nil {
+		log.Printf("Initial check failed: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := controller.PerformCheck(ctx); err != nil {
+				log.Printf("Check failed: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// domainNames extracts the domain names from domains for a concise startup
+// log line.
+func domainNames(domains []common.DomainConfig) []string {
+	names := make([]string, len(domains))
+	for i, d := range domains {
+		names[i] = d.Domain
+	}
+	return names
+}
@@ -3,18 +3,29 @@ package common
 import (
 	"errors"
 	"fmt"
-	"log"
-	"net/http"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/miekg/dns"
 )
 
 const (
 
+	// NotifierURLsEnv holds a whitespace-separated list of shoutrrr-style
+	// notifier URLs, e.g. "pushover://token@userKey/?priority=1".
+	NotifierURLsEnv = "NOTIFIER_URLS"
+
+	// NotificationTitleTagEnv, when set, is prepended as "[tag]" to every
+	// notification title.
+	NotificationTitleTagEnv = "NOTIFICATION_TITLE_TAG"
+	// NotificationSkipTitleEnv, set to "true", omits the title entirely for
+	// providers that support untitled messages.
+	NotificationSkipTitleEnv = "NOTIFICATION_SKIP_TITLE"
+	// HeartbeatIntervalEnv, when set, sends a periodic "still alive"
+	// notification at the given duration (e.g. "1h").
+	HeartbeatIntervalEnv = "HEARTBEAT_INTERVAL"
+
 	// Pushover config
 	PushoverUserKeyEnv  = "PUSHOVER_USER_KEY"
 	PushoverAppTokenEnv = "PUSHOVER_APP_TOKEN"
@@ -23,91 +34,156 @@ const (
 	TelegramBotTokenEnv = "TELEGRAM_BOT_TOKEN"
 	TelegramChatIDsEnv  = "TELEGRAM_CHAT_IDS" // Comma-separated list of chat IDs
 
+	// Webhook config
+	WebhookURLEnv           = "WEBHOOK_URL"
+	WebhookHeadersEnv       = "WEBHOOK_HEADERS" // Comma-separated list of Key=Value pairs
+	WebhookCAFileEnv        = "WEBHOOK_CA_FILE"
+	WebhookCertFileEnv      = "WEBHOOK_CERT_FILE"
+	WebhookKeyFileEnv       = "WEBHOOK_KEY_FILE"
+	WebhookServerNameEnv    = "WEBHOOK_SERVER_NAME"
+	WebhookSigningSecretEnv = "WEBHOOK_SIGNING_SECRET"
+
+	// Script config
+	ScriptPathEnv = "SCRIPT_PATH"
+
+	// DomainsEnv holds a comma-separated list of domains to monitor, e.g.
+	// "example.com,example.org". Each domain can be further configured via
+	// suffixed env vars, e.g. CUSTOM_SUBDOMAINS_EXAMPLE_COM,
+	// CUSTOM_DKIM_SELECTORS_EXAMPLE_COM, CHECK_INTERVAL_EXAMPLE_COM, and
+	// DNS_SERVER_EXAMPLE_COM. When unset, the single DOMAIN/
+	// CUSTOM_SUBDOMAINS/CUSTOM_DKIM_SELECTORS/CHECK_INTERVAL env vars are
+	// used as-is, for backward compatibility.
+	DomainsEnv = "DOMAINS"
+
+	// DNS transport config
+	DNSProtocolEnv           = "DNS_PROTOCOL"
+	DNSTLSCAFileEnv          = "DNS_TLS_CA_FILE"
+	DNSInsecureSkipVerifyEnv = "DNS_INSECURE_SKIP_VERIFY"
+	DNSPinnedSPKIEnv         = "DNS_PINNED_SPKI"
+	DNSSECEnabledEnv         = "DNSSEC_ENABLED"
+	DNSSECExpiryWindowEnv    = "DNSSEC_EXPIRY_WINDOW"
+
+	// Propagation checker config
+	PropagationResolversEnv = "PROPAGATION_RESOLVERS" // Comma-separated list of resolver addresses
+	PropagationTimeoutEnv   = "PROPAGATION_TIMEOUT"
+	PropagationIntervalEnv  = "PROPAGATION_INTERVAL"
+
+	// MetricsAddrEnv, when set, enables the Prometheus /metrics endpoint on
+	// the given address, e.g. ":9090".
+	MetricsAddrEnv = "METRICS_ADDR"
+
+	// ExpectedStateFileEnv, when set, enables desired-state drift detection
+	// against the zone file or YAML baseline at the given path.
+	ExpectedStateFileEnv = "EXPECTED_STATE_FILE"
+
+	// Ignored-record filter config
+	IgnoredNamesEnv   = "IGNORED_NAMES"   // Comma-separated path.Match globs on record Name
+	IgnoredTargetsEnv = "IGNORED_TARGETS" // Comma-separated path.Match globs on record Value
+	IgnoredTypesEnv   = "IGNORED_TYPES"   // Comma-separated list of record types
+	DryRunFilterEnv   = "DRY_RUN_FILTER"
+
 	// Add more environment variables for other providers as needed
 
 )
 
-// Load configuration from environment variables with validation
-func LoadConfig() (*Config, error) {
-	domain := os.Getenv("DOMAIN")
-	if domain == "" {
-		return &Config{}, errors.New("DOMAIN environment variable is required (e.g., test.ro)")
-	}
-
-	notificationConfig, err := loadNotificationConfig()
-	if err != nil {
-		return &Config{}, fmt.Errorf("failed to load notification config: %v", err)
+// domainSuffix converts a domain name into an env-var-safe suffix (e.g.
+// "example.com" -> "EXAMPLE_COM") used to build per-domain override env
+// vars such as CUSTOM_SUBDOMAINS_EXAMPLE_COM.
+func domainSuffix(domain string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(domain) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
 	}
+	return b.String()
+}
 
-	// DNS server with default
-	dnsServer := os.Getenv("DNS_SERVER")
-	if dnsServer == "" {
-		dnsServer = "1.1.1.1:53"
+// buildDomainConfigs resolves the list of domains to monitor from the
+// DOMAINS env var and its per-domain suffixed overrides. When DOMAINS is
+// unset, it falls back to a single DomainConfig built from the legacy
+// DOMAIN/CUSTOM_SUBDOMAINS/CUSTOM_DKIM_SELECTORS/CHECK_INTERVAL env vars,
+// so existing single-domain deployments keep working unchanged.
+func buildDomainConfigs(primaryDomain string, primarySubdomains, primarySelectors []string, primaryInterval time.Duration) ([]DomainConfig, error) {
+	rawDomains := os.Getenv(DomainsEnv)
+	if rawDomains == "" {
+		return []DomainConfig{{
+			Domain:              primaryDomain,
+			CustomSubdomains:    primarySubdomains,
+			CustomDkimSelectors: primarySelectors,
+			CheckInterval:       primaryInterval,
+		}}, nil
 	}
 
-	// Check interval with default and validation
-	checkInterval := 1 * time.Hour
-	intervalStr := os.Getenv("CHECK_INTERVAL")
-	if intervalStr != "" {
-		duration, err := time.ParseDuration(intervalStr)
-		if err != nil {
-			return &Config{}, fmt.Errorf("invalid CHECK_INTERVAL format: %v", err)
+	var domains []DomainConfig
+	for _, domain := range strings.Split(rawDomains, ",") {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
 		}
-		if duration < 1*time.Minute {
-			log.Println("⚠️ Warning: CHECK_INTERVAL less than 1 minute may cause excessive API calls ⚠️")
+
+		suffix := domainSuffix(domain)
+
+		interval := primaryInterval
+		if raw := os.Getenv("CHECK_INTERVAL_" + suffix); raw != "" {
+			duration, err := time.ParseDuration(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CHECK_INTERVAL_%s format: %v", suffix, err)
+			}
+			interval = duration
 		}
-		checkInterval = duration
-	}
 
-	// Notification on errors setting
-	notifyOnErrors := false
-	if os.Getenv("NOTIFY_ON_ERRORS") == "true" {
-		notifyOnErrors = true
+		domains = append(domains, DomainConfig{
+			Domain:              domain,
+			CustomSubdomains:    getValidEntries("CUSTOM_SUBDOMAINS_"+suffix, parseString),
+			CustomDkimSelectors: getValidEntries("CUSTOM_DKIM_SELECTORS_"+suffix, parseString),
+			CheckInterval:       interval,
+			DNSServer:           os.Getenv("DNS_SERVER_" + suffix),
+		})
 	}
-	log.Printf("🔔 Notify on errors: %v 🔔", notifyOnErrors)
 
-	validCustomSubdomains := getValidEntries("CUSTOM_SUBDOMAINS", parseString)
-	log.Printf("🌐 Custom subdomains: %v 🌐", validCustomSubdomains)
+	return domains, nil
+}
 
-	validCustomDkimSelectors := getValidEntries("CUSTOM_DKIM_SELECTORS", parseString)
-	log.Printf("🛡️ Custom DKIM selectors: %v 🛡️", validCustomDkimSelectors)
+// NotificationConfig holds the configuration for notification services.
+// NOTIFIER_URLS takes precedence over the legacy NOTIFIER_TYPE scheme; the
+// latter is kept only for users who haven't migrated yet.
+func loadNotificationConfig() (*NotificationConfig, error) {
+	titleTag := os.Getenv(NotificationTitleTagEnv)
+	skipTitle := os.Getenv(NotificationSkipTitleEnv) == "true"
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			DisableKeepAlives: false,
-			IdleConnTimeout:   90 * time.Second,
-			MaxIdleConns:      100,
-			MaxConnsPerHost:   10,
-		},
+	var heartbeatInterval time.Duration
+	if raw := os.Getenv(HeartbeatIntervalEnv); raw != "" {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return &NotificationConfig{}, fmt.Errorf("invalid %s format: %v", HeartbeatIntervalEnv, err)
+		}
+		heartbeatInterval = duration
 	}
 
-	// Create DNS Client
-	dnsClient := new(dns.Client)
-
-	return &Config{
-		Domain:              domain,
-		CustomSubdomains:    validCustomSubdomains,
-		CustomDkimSelectors: validCustomDkimSelectors,
-		DNSServer:           dnsServer,
-		DNSClient:           *dnsClient,
-		CheckInterval:       checkInterval,
-		HTTPClient:          client,
-		NotificationConfig:  *notificationConfig,
-		NotifyOnErrors:      notifyOnErrors,
-	}, nil
-
-}
+	if rawURLs := os.Getenv(NotifierURLsEnv); rawURLs != "" {
+		// Notifier URLs are whitespace-separated rather than comma-separated,
+		// since a single provider URL can itself contain commas in its query
+		// values (e.g. a Telegram chat ID list).
+		return &NotificationConfig{
+			NotifierURLs:      strings.Fields(rawURLs),
+			TitleTag:          titleTag,
+			SkipTitle:         skipTitle,
+			HeartbeatInterval: heartbeatInterval,
+		}, nil
+	}
 
-// NotificationConfig holds the configuration for notification services
-func loadNotificationConfig() (*NotificationConfig, error) {
 	notifierType := os.Getenv("NOTIFIER_TYPE")
 	if !IsValidNotifierType(notifierType) {
 		return &NotificationConfig{}, errors.New("NOTIFIER_TYPE environment variable is required")
 	}
 	config := NotificationConfig{
-		NotifierType: notifierType,
+		NotifierType:      notifierType,
+		TitleTag:          titleTag,
+		SkipTitle:         skipTitle,
+		HeartbeatInterval: heartbeatInterval,
 	}
 	switch notifierType {
 	case NotifierTypePushover:
@@ -122,6 +198,22 @@ func loadNotificationConfig() (*NotificationConfig, error) {
 		if config.TelegramBotToken == "" || len(config.TelegramChatIDs) == 0 {
 			return nil, fmt.Errorf("%s and %s environment variable are required", TelegramBotTokenEnv, TelegramChatIDsEnv)
 		}
+	case NotifierTypeWebhook:
+		config.WebhookURL = os.Getenv(WebhookURLEnv)
+		if config.WebhookURL == "" {
+			return nil, fmt.Errorf("%s environment variable is required", WebhookURLEnv)
+		}
+		config.WebhookHeaders = parseWebhookHeaders(os.Getenv(WebhookHeadersEnv))
+		config.WebhookCAFile = os.Getenv(WebhookCAFileEnv)
+		config.WebhookCertFile = os.Getenv(WebhookCertFileEnv)
+		config.WebhookKeyFile = os.Getenv(WebhookKeyFileEnv)
+		config.WebhookServerName = os.Getenv(WebhookServerNameEnv)
+		config.WebhookSigningSecret = os.Getenv(WebhookSigningSecretEnv)
+	case NotifierTypeScript:
+		config.ScriptPath = os.Getenv(ScriptPathEnv)
+		if config.ScriptPath == "" {
+			return nil, fmt.Errorf("%s environment variable is required", ScriptPathEnv)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported notifier type: %s", notifierType)
 	}
@@ -142,6 +234,33 @@ func IsValidNotifierType(input string) bool {
 	return false
 }
 
+// DNSProtocols lists the supported DNS transport protocols.
+var DNSProtocols = []string{DNSProtocolUDP, DNSProtocolTCP, DNSProtocolTLS, DNSProtocolHTTPS}
+
+// IsValidDNSProtocol checks if the provided DNS transport protocol is valid.
+func IsValidDNSProtocol(input string) bool {
+	for _, protocol := range DNSProtocols {
+		if strings.EqualFold(input, protocol) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGlobPatterns reports an error naming the first malformed pattern
+// in patterns, using path.Match's own syntax rules (the same ones
+// dns.FilterRecords matches IgnoredNames/IgnoredTargets against). Checked at
+// config load time so a typo'd pattern fails fast instead of silently never
+// matching anything on every check thereafter.
+func ValidateGlobPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
 // getValidEntries fetches and processes a comma-separated environment variable.
 func getValidEntries[T comparable](envVar string, parseFunc func(string) (T, error)) []T {
 	rawValue := os.Getenv(envVar)
@@ -169,6 +288,23 @@ func getValidEntries[T comparable](envVar string, parseFunc func(string) (T, err
 	return validEntries
 }
 
+// parseWebhookHeaders parses a comma-separated list of "Key=Value" pairs into a header map.
+func parseWebhookHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	if raw == "" {
+		return headers
+	}
+
+	for _, entry := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || key == "" {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
 // Helper functions for parsing
 func parseString(value string) (string, error) {
 	return value, nil
@@ -10,11 +10,24 @@ import (
 // RetryWithExponentialBackoff retries the provided operation with an exponential backoff.
 // It stops retrying when the operation succeeds or when the context is done.
 func RetryWithExponentialBackoff(ctx context.Context, attempts int, initialDelay time.Duration, operation func() error) error {
+	return RetryWithExponentialBackoffHook(ctx, attempts, initialDelay, operation, nil)
+}
+
+// RetryWithExponentialBackoffHook behaves like RetryWithExponentialBackoff,
+// additionally invoking onAttempt after every attempt with whether it
+// succeeded. This lets a caller observe retries (e.g. to increment a
+// metric) without this package depending on anything outside the standard
+// library: onAttempt is a plain func value, not a type this package has to
+// export. onAttempt may be nil.
+func RetryWithExponentialBackoffHook(ctx context.Context, attempts int, initialDelay time.Duration, operation func() error, onAttempt func(success bool)) error {
 	delay := initialDelay
 	var lastErr error
 
 	for i := 0; i < attempts; i++ {
 		err := operation()
+		if onAttempt != nil {
+			onAttempt(err == nil)
+		}
 		if err == nil {
 			if i != 0 { // Exclude the first attempt from logging
 				log.Printf("Operation succeeded on attempt %d", i+1)
@@ -49,6 +49,38 @@ func TestRetryWithExponentialBackoff_EventualSuccess(t *testing.T) {
 	}
 }
 
+func TestRetryWithExponentialBackoffHook_ReportsEachAttempt(t *testing.T) {
+	var attempt int
+	operation := func() error {
+		attempt++
+		if attempt < 3 {
+			return errors.New("failure")
+		}
+		return nil
+	}
+
+	var outcomes []bool
+	onAttempt := func(success bool) {
+		outcomes = append(outcomes, success)
+	}
+
+	ctx := context.Background()
+	err := RetryWithExponentialBackoffHook(ctx, 5, time.Millisecond, operation, onAttempt)
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+
+	expected := []bool{false, false, true}
+	if len(outcomes) != len(expected) {
+		t.Fatalf("expected %d reported attempts, got %d", len(expected), len(outcomes))
+	}
+	for i, want := range expected {
+		if outcomes[i] != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, outcomes[i])
+		}
+	}
+}
+
 func TestRetryWithExponentialBackoff_AllFailures(t *testing.T) {
 	var attempt int
 	expectedErr := errors.New("permanent failure")
@@ -0,0 +1,220 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Transport sends one DNS query and returns the response. queryDNS builds
+// one from a Config via NewTransport and reuses it across retries, so the
+// plain UDP/TCP, DoT, and DoH implementations all share the same retry and
+// backoff wrapper.
+type Transport interface {
+	Exchange(ctx context.Context, m *dns.Msg) (*dns.Msg, error)
+}
+
+// UDPTransport queries over plain UDP, falling back to TCP on truncation per
+// dns.Client's default behavior.
+type UDPTransport struct {
+	Server string
+	Client dns.Client
+}
+
+func (t UDPTransport) Exchange(_ context.Context, m *dns.Msg) (*dns.Msg, error) {
+	client := t.Client
+	client.Net = ""
+	resp, _, err := client.Exchange(m, t.Server)
+	return resp, err
+}
+
+// TCPTransport queries over plain TCP.
+type TCPTransport struct {
+	Server string
+	Client dns.Client
+}
+
+func (t TCPTransport) Exchange(_ context.Context, m *dns.Msg) (*dns.Msg, error) {
+	client := t.Client
+	client.Net = "tcp"
+	resp, _, err := client.Exchange(m, t.Server)
+	return resp, err
+}
+
+// DoTTransport queries over DNS-over-TLS (RFC 7858) by reusing dns.Client
+// with Net set to "tcp-tls".
+type DoTTransport struct {
+	Server    string
+	Client    dns.Client
+	TLSConfig *tls.Config
+}
+
+func (t DoTTransport) Exchange(_ context.Context, m *dns.Msg) (*dns.Msg, error) {
+	client := t.Client
+	client.Net = "tcp-tls"
+	client.TLSConfig = t.TLSConfig
+	resp, _, err := client.Exchange(m, t.Server)
+	return resp, err
+}
+
+// DoHTransport queries over DNS-over-HTTPS (RFC 8484): the wire-format query
+// is POSTed to Server and the response is unpacked back into a dns.Msg, so
+// callers can reuse the same parseDNSRecord pipeline regardless of
+// transport.
+type DoHTransport struct {
+	Server     string
+	HTTPClient *http.Client
+}
+
+func (t DoHTransport) Exchange(ctx context.Context, m *dns.Msg) (*dns.Msg, error) {
+	packed, err := m.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack DoH query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Server, bytes.NewReader(packed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DoH request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	httpClient := t.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("DoH request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server returned non-200 status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DoH response body: %w", err)
+	}
+
+	reply := new(dns.Msg)
+	if err := reply.Unpack(body); err != nil {
+		return nil, fmt.Errorf("failed to unpack DoH response: %w", err)
+	}
+
+	return reply, nil
+}
+
+// NewTransport builds the Transport for reaching server. The protocol is
+// taken from a URL-style scheme on server when there is one (e.g.
+// "https://cloudflare-dns.com/dns-query" or "tls://1.1.1.1:853"), so a
+// per-domain DNSServer override can select its own transport independently
+// of config.DNSProtocol; otherwise it falls back to config.DNSProtocol.
+func NewTransport(config Config, server string) (Transport, error) {
+	protocol, server := splitDNSServerScheme(server, config.DNSProtocol)
+
+	switch strings.ToLower(protocol) {
+	case DNSProtocolTCP:
+		return TCPTransport{Server: server, Client: config.DNSClient}, nil
+	case DNSProtocolTLS:
+		tlsConfig, err := buildDoTTLSConfig(config, server)
+		if err != nil {
+			return nil, err
+		}
+		return DoTTransport{Server: server, Client: config.DNSClient, TLSConfig: tlsConfig}, nil
+	case DNSProtocolHTTPS:
+		return DoHTransport{Server: server, HTTPClient: config.HTTPClient}, nil
+	default:
+		return UDPTransport{Server: server, Client: config.DNSClient}, nil
+	}
+}
+
+// splitDNSServerScheme strips a "udp://", "tcp://", "tls://", or "https://"
+// scheme from server and returns the protocol it selects along with the bare
+// server address. A "https://" scheme keeps the full URL, since DoH needs it
+// intact as the query endpoint. A server with no recognized scheme is
+// returned as-is, with fallback as the protocol.
+func splitDNSServerScheme(server, fallback string) (protocol, bareServer string) {
+	switch {
+	case strings.HasPrefix(server, "https://"):
+		return DNSProtocolHTTPS, server
+	case strings.HasPrefix(server, "tls://"):
+		return DNSProtocolTLS, strings.TrimPrefix(server, "tls://")
+	case strings.HasPrefix(server, "tcp://"):
+		return DNSProtocolTCP, strings.TrimPrefix(server, "tcp://")
+	case strings.HasPrefix(server, "udp://"):
+		return DNSProtocolUDP, strings.TrimPrefix(server, "udp://")
+	default:
+		return fallback, server
+	}
+}
+
+// buildDoTTLSConfig derives the TLS config for a DNS-over-TLS connection to
+// server, setting ServerName from its host and honoring an optional CA
+// bundle, pinned SPKI hash, and INSECURE_SKIP_VERIFY toggle for testing.
+func buildDoTTLSConfig(config Config, server string) (*tls.Config, error) {
+	serverName := server
+	if host, _, err := net.SplitHostPort(server); err == nil {
+		serverName = host
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: config.DNSInsecureSkipVerify,
+	}
+
+	if config.DNSTLSCAFile != "" {
+		caCert, err := os.ReadFile(config.DNSTLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read DNS TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse DNS TLS CA file: %s", config.DNSTLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.DNSPinnedSPKI != "" {
+		// Pin checking takes over certificate validation entirely, the same
+		// way HPKP's pin-sha256 did: skip the normal chain verification and
+		// accept the connection only if some presented certificate's
+		// SubjectPublicKeyInfo hash matches the pin.
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyPinnedSPKI(config.DNSPinnedSPKI)
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyPinnedSPKI returns a tls.Config.VerifyPeerCertificate callback that
+// accepts the connection only if one of the presented certificates' SPKI
+// hash (base64-encoded SHA-256 of RawSubjectPublicKeyInfo) matches pin.
+func verifyPinnedSPKI(pin string) func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				continue
+			}
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			if base64.StdEncoding.EncodeToString(sum[:]) == pin {
+				return nil
+			}
+		}
+		return fmt.Errorf("no presented certificate matched pinned SPKI %s", pin)
+	}
+}
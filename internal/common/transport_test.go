@@ -0,0 +1,107 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestSplitDNSServerScheme(t *testing.T) {
+	tests := []struct {
+		name       string
+		server     string
+		fallback   string
+		wantProto  string
+		wantServer string
+	}{
+		{"https scheme keeps full URL", "https://cloudflare-dns.com/dns-query", DNSProtocolUDP, DNSProtocolHTTPS, "https://cloudflare-dns.com/dns-query"},
+		{"tls scheme strips prefix", "tls://1.1.1.1:853", DNSProtocolUDP, DNSProtocolTLS, "1.1.1.1:853"},
+		{"tcp scheme strips prefix", "tcp://1.1.1.1:53", DNSProtocolUDP, DNSProtocolTCP, "1.1.1.1:53"},
+		{"udp scheme strips prefix", "udp://1.1.1.1:53", DNSProtocolTLS, DNSProtocolUDP, "1.1.1.1:53"},
+		{"no scheme falls back", "1.1.1.1:53", DNSProtocolTCP, DNSProtocolTCP, "1.1.1.1:53"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protocol, server := splitDNSServerScheme(tt.server, tt.fallback)
+			if protocol != tt.wantProto {
+				t.Errorf("expected protocol %q, got %q", tt.wantProto, protocol)
+			}
+			if server != tt.wantServer {
+				t.Errorf("expected server %q, got %q", tt.wantServer, server)
+			}
+		})
+	}
+}
+
+func TestNewTransport_SchemeOverridesDNSProtocol(t *testing.T) {
+	config := Config{DNSProtocol: DNSProtocolUDP}
+
+	transport, err := NewTransport(config, "tcp://1.1.1.1:53")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := transport.(TCPTransport); !ok {
+		t.Errorf("expected TCPTransport, got %T", transport)
+	}
+}
+
+func TestDoHTransport_Exchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+
+		req := new(dns.Msg)
+		if err := req.Unpack(body); err != nil {
+			t.Fatalf("failed to unpack request: %v", err)
+		}
+
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		rr, _ := dns.NewRR(req.Question[0].Name + " 3600 IN A 192.0.2.1")
+		resp.Answer = append(resp.Answer, rr)
+
+		packed, err := resp.Pack()
+		if err != nil {
+			t.Fatalf("failed to pack response: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(packed)
+	}))
+	defer server.Close()
+
+	transport := DoHTransport{Server: server.URL, HTTPClient: server.Client()}
+
+	m := new(dns.Msg)
+	m.SetQuestion("example.com.", dns.TypeA)
+
+	resp, err := transport.Exchange(context.Background(), m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Answer) != 1 {
+		t.Errorf("expected 1 answer, got %d", len(resp.Answer))
+	}
+}
+
+func TestBuildDoTTLSConfig_PinnedSPKIImpliesInsecureSkipVerify(t *testing.T) {
+	config := Config{DNSPinnedSPKI: "deadbeef"}
+
+	tlsConfig, err := buildDoTTLSConfig(config, "1.1.1.1:853")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("expected InsecureSkipVerify to be true when a pinned SPKI is set")
+	}
+	if tlsConfig.VerifyPeerCertificate == nil {
+		t.Error("expected VerifyPeerCertificate to be set when a pinned SPKI is set")
+	}
+}
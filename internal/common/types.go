@@ -9,6 +9,10 @@ import (
 
 type PreviousState struct {
 	Records []DNSRecord `json:"records"`
+	// DNSKeyTags holds the zone's DNSKEY key tags as of the last check, when
+	// DNSSEC checking is enabled, so the next check can tell a key rollover
+	// apart from a flood of RRSIG ADDED/REMOVED lines. See dns.CheckDNSSEC.
+	DNSKeyTags []uint16 `json:"dnskey_tags,omitempty"`
 }
 
 type DNSRecord struct {
@@ -18,20 +22,162 @@ type DNSRecord struct {
 	TTL   uint32 `json:"ttl"`
 }
 
+// DomainConfig holds the per-domain settings needed to monitor one domain:
+// its subdomains, DKIM selectors, check interval, and an optional DNS
+// server override. Config.Domains holds one of these per monitored domain,
+// so a single process can watch a portfolio of domains, each on its own
+// polling schedule.
+type DomainConfig struct {
+	Domain              string
+	CustomSubdomains    []string
+	CustomDkimSelectors []string
+	CheckInterval       time.Duration
+	// DNSServer, when set, overrides Config.DNSServer for this domain only.
+	DNSServer string
+}
+
 // Configuration struct to hold all settings
 type Config struct {
 	Domain              string
-	CustomDomains       []string
+	CustomSubdomains    []string
 	CustomDkimSelectors []string
 	DNSServer           string
 	DNSClient           dns.Client
+	// Domains lists every domain to monitor, each with its own overrides.
+	// It always has at least one entry: LoadConfigFromViper populates it
+	// from either the legacy single-domain settings above or the
+	// DOMAINS/domains multi-domain configuration.
+	Domains []DomainConfig
+	// DNSProtocol selects the transport used to reach DNSServer: "udp"
+	// (default), "tcp", "tls" (DNS-over-TLS), or "https" (DNS-over-HTTPS,
+	// where DNSServer is the query URL). DNSServer may instead carry its own
+	// "udp://", "tcp://", "tls://", or "https://" scheme (e.g.
+	// "tls://1.1.1.1:853"), which takes precedence over DNSProtocol; see
+	// NewTransport.
+	DNSProtocol string
+	// DNSTLSCAFile, when set, overrides the system root CA bundle used to
+	// verify the DoT server's certificate.
+	DNSTLSCAFile string
+	// DNSInsecureSkipVerify disables certificate verification for DoT. Only
+	// meant for testing against a self-signed fake server.
+	DNSInsecureSkipVerify bool
+	// DNSPinnedSPKI, when set, pins the DoT server's certificate by its
+	// base64-encoded SHA-256 SubjectPublicKeyInfo hash instead of verifying
+	// it against DNSTLSCAFile or the system roots.
+	DNSPinnedSPKI string
+	// DNSSECEnabled sets the DO bit on outgoing queries and captures RRSIG,
+	// DNSKEY, DS, and NSEC/NSEC3 records alongside the usual record types,
+	// so checks can validate signatures and report DNSSEC events. See
+	// dns.CheckDNSSEC.
+	DNSSECEnabled bool
+	// DNSSECExpiryWindow, when non-zero, reports an RRSIG whose Expiration
+	// falls within this window of now as a "DNSSEC signature about to
+	// expire" event. Has no effect when DNSSECEnabled is false.
+	DNSSECExpiryWindow time.Duration
+	// PropagationResolvers, when non-empty, enables the propagation
+	// checker: after a check detects a change, every changed RRset is
+	// re-queried against each of these resolvers (e.g.
+	// "1.1.1.1:53", "8.8.8.8:53") until they all agree with the new value
+	// or PropagationTimeout elapses. Leaving it empty sends the raw diff
+	// immediately, with no propagation wait. See dns.CheckPropagation.
+	PropagationResolvers []string
+	// PropagationTimeout bounds how long CheckPropagation polls the
+	// resolvers in PropagationResolvers before reporting a partial
+	// propagation instead of waiting indefinitely.
+	PropagationTimeout time.Duration
+	// PropagationInterval is the delay between successive polling rounds
+	// in CheckPropagation.
+	PropagationInterval time.Duration
 	CheckInterval       time.Duration
 	HTTPClient          *http.Client
 	NotifyOnErrors      bool
 	NotificationConfig  NotificationConfig
+	// HTTPListenAddr is the address the embedded status/control API listens
+	// on, e.g. ":8080".
+	HTTPListenAddr string
+	// MetricsAddr, when set, is the address a separate /metrics endpoint
+	// listens on, e.g. ":9090". Prometheus metrics are opt-in: leaving this
+	// empty disables the endpoint entirely.
+	MetricsAddr string
+	// ExpectedStateFile, when set, points at a desired-state baseline (an
+	// RFC 1035 zone file, or a YAML list of DNSRecord entries) that every
+	// check is also diffed against via DetectDrift, in addition to the
+	// usual diff against the previous snapshot. Leaving it empty disables
+	// desired-state mode entirely.
+	ExpectedStateFile string
+	// IgnoredNames, IgnoredTargets, and IgnoredTypes drop records matching a
+	// path.Match glob on, respectively, the record Name (e.g.
+	// "*.dyn.example.com"), the record Value (e.g. "*.cloudfront.net"), or
+	// an exact record Type (e.g. "TXT"), before FetchDNSRecords's results
+	// reach diff.Diff or DetectDrift. This lets operators monitor zones with
+	// machine-generated churn (ACME challenges, dynamic DNS, ephemeral
+	// CNAMEs) without getting paged on every rotation. See dns.FilterRecords.
+	IgnoredNames   []string
+	IgnoredTargets []string
+	IgnoredTypes   []string
+	// DryRunFilter, when true, doesn't drop ignored records: it logs which
+	// ones IgnoredNames/IgnoredTargets/IgnoredTypes would have ignored, so
+	// operators can validate their patterns before committing to them.
+	DryRunFilter bool
+}
+
+// PrimaryDomainConfig returns the DomainConfig view of c's legacy top-level
+// single-domain fields, for callers that only know about one domain at a
+// time (e.g. a Controller bound to a single Config).
+func (c Config) PrimaryDomainConfig() DomainConfig {
+	return DomainConfig{
+		Domain:              c.Domain,
+		CustomSubdomains:    c.CustomSubdomains,
+		CustomDkimSelectors: c.CustomDkimSelectors,
+		CheckInterval:       c.CheckInterval,
+	}
 }
 
+// ForDomain returns a copy of c scoped to one entry of c.Domains: Domain,
+// CustomSubdomains, CustomDkimSelectors, and CheckInterval are overridden
+// from d, and DNSServer is overridden only when d.DNSServer is set. This
+// lets each monitored domain run behind its own Controller while sharing
+// the rest of c's configuration (DNS transport, HTTP client, notifier).
+func (c Config) ForDomain(d DomainConfig) Config {
+	scoped := c
+	scoped.Domain = d.Domain
+	scoped.CustomSubdomains = d.CustomSubdomains
+	scoped.CustomDkimSelectors = d.CustomDkimSelectors
+	scoped.CheckInterval = d.CheckInterval
+	if d.DNSServer != "" {
+		scoped.DNSServer = d.DNSServer
+	}
+	return scoped
+}
+
+// DNS transport protocols
+const (
+	DNSProtocolUDP   = "udp"
+	DNSProtocolTCP   = "tcp"
+	DNSProtocolTLS   = "tls"
+	DNSProtocolHTTPS = "https"
+)
+
 type NotificationConfig struct {
+	// NotifierURLs holds one or more shoutrrr-style notifier URLs
+	// (e.g. "pushover://token@userKey/?priority=1"). When set, this is the
+	// primary configuration path and every URL is fanned out to on send.
+	NotifierURLs []string
+
+	// TitleTag, when set, is prepended as "[TitleTag]" to every notification
+	// title, so operators running many dns-monitor instances can tell them
+	// apart by host/domain.
+	TitleTag string
+	// SkipTitle omits the title entirely for providers that support
+	// untitled messages, folding it into the message body instead.
+	SkipTitle bool
+	// HeartbeatInterval, when non-zero, sends a periodic "still alive"
+	// notification so operators can detect a silently crashed instance.
+	HeartbeatInterval time.Duration
+
+	// NotifierType and the fields below are the legacy single-provider
+	// configuration, kept as a fallback for users who haven't migrated to
+	// NotifierURLs yet.
 	NotifierType string
 	// Pushover configuration
 	PushoverToken string
@@ -39,6 +185,16 @@ type NotificationConfig struct {
 	// Telegram configuration
 	TelegramBotToken string
 	TelegramChatIDs  []int64
+	// Webhook configuration
+	WebhookURL           string
+	WebhookHeaders       map[string]string
+	WebhookCAFile        string
+	WebhookCertFile      string
+	WebhookKeyFile       string
+	WebhookServerName    string
+	WebhookSigningSecret string
+	// Script configuration
+	ScriptPath string
 }
 
 // Notifier types
@@ -47,6 +203,8 @@ const (
 	NotifierTypeTelegram = "telegram"
 	NotifierTypeSlack    = "slack"
 	NotifierTypeEmail    = "email"
+	NotifierTypeWebhook  = "webhook"
+	NotifierTypeScript   = "script"
 	// Add more notifier types as needed
 )
 
@@ -55,5 +213,7 @@ var NotifierTypes = []string{
 	NotifierTypeTelegram,
 	NotifierTypeSlack,
 	NotifierTypeEmail,
+	NotifierTypeWebhook,
+	NotifierTypeScript,
 	// Add more notifier types as needed
 }
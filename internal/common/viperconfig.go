@@ -0,0 +1,340 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/spf13/viper"
+)
+
+// NotifierBlock is one entry of the declarative "notifiers" list in a config
+// file, describing a single notifier instance.
+type NotifierBlock struct {
+	Type          string            `mapstructure:"type"`
+	Token         string            `mapstructure:"token"`
+	User          string            `mapstructure:"user"`
+	BotToken      string            `mapstructure:"bot_token"`
+	Chats         []int64           `mapstructure:"chats"`
+	Channels      []string          `mapstructure:"channels"`
+	Host          string            `mapstructure:"host"`
+	Port          int               `mapstructure:"port"`
+	Username      string            `mapstructure:"username"`
+	Password      string            `mapstructure:"password"`
+	From          string            `mapstructure:"from"`
+	To            []string          `mapstructure:"to"`
+	Headers       map[string]string `mapstructure:"headers"`
+	Path          string            `mapstructure:"path"`
+	Insecure      bool              `mapstructure:"insecure"`
+	SigningSecret string            `mapstructure:"signing_secret"`
+	CAFile        string            `mapstructure:"ca_file"`
+	CertFile      string            `mapstructure:"cert_file"`
+	KeyFile       string            `mapstructure:"key_file"`
+	ServerName    string            `mapstructure:"server_name"`
+}
+
+// ToNotifierURL converts the block into the shoutrrr-style URL understood by
+// the provider registry, so declarative config and NOTIFIER_URLS share one
+// parsing path.
+func (b NotifierBlock) ToNotifierURL() (string, error) {
+	switch b.Type {
+	case NotifierTypePushover:
+		if b.Token == "" || b.User == "" {
+			return "", fmt.Errorf("pushover notifier requires token and user")
+		}
+		return fmt.Sprintf("pushover://%s@%s", b.Token, b.User), nil
+	case NotifierTypeTelegram:
+		if b.BotToken == "" || len(b.Chats) == 0 {
+			return "", fmt.Errorf("telegram notifier requires bot_token and chats")
+		}
+		chatStrs := make([]string, len(b.Chats))
+		for i, chat := range b.Chats {
+			chatStrs[i] = strconv.FormatInt(chat, 10)
+		}
+		return fmt.Sprintf("telegram://%s@telegram?chats=%s", b.BotToken, strings.Join(chatStrs, ",")), nil
+	case "discord":
+		if b.Token == "" || len(b.Channels) == 0 {
+			return "", fmt.Errorf("discord notifier requires token and channels")
+		}
+		return fmt.Sprintf("discord://%s@%s", b.Token, b.Channels[0]), nil
+	case NotifierTypeSlack:
+		if b.Token == "" || len(b.Channels) == 0 {
+			return "", fmt.Errorf("slack notifier requires token and channels")
+		}
+		return fmt.Sprintf("slack://%s@%s", b.Token, strings.Join(b.Channels, ",")), nil
+	case "smtp":
+		if b.Host == "" || b.From == "" || len(b.To) == 0 {
+			return "", fmt.Errorf("smtp notifier requires host, from, and to")
+		}
+		port := b.Port
+		if port == 0 {
+			port = 25
+		}
+		u := url.URL{
+			Scheme: "smtp",
+			User:   url.UserPassword(b.Username, b.Password),
+			Host:   fmt.Sprintf("%s:%d", b.Host, port),
+		}
+		query := url.Values{}
+		query.Set("fromAddress", b.From)
+		query.Set("toAddresses", strings.Join(b.To, ","))
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	case "mattermost":
+		if b.Host == "" || b.Username == "" || b.Password == "" || len(b.Channels) == 0 {
+			return "", fmt.Errorf("mattermost notifier requires host, username, password, and channels")
+		}
+		u := url.URL{
+			Scheme: "mattermost",
+			User:   url.UserPassword(b.Username, b.Password),
+			Host:   b.Host,
+			Path:   b.Path,
+		}
+		query := url.Values{}
+		query.Set("channels", strings.Join(b.Channels, ","))
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	case NotifierTypeWebhook:
+		if b.Host == "" {
+			return "", fmt.Errorf("webhook notifier requires host")
+		}
+		host := b.Host
+		if b.Port != 0 {
+			host = fmt.Sprintf("%s:%d", b.Host, b.Port)
+		}
+		query := url.Values{}
+		if len(b.Headers) > 0 {
+			pairs := make([]string, 0, len(b.Headers))
+			for key, value := range b.Headers {
+				pairs = append(pairs, key+"="+value)
+			}
+			sort.Strings(pairs)
+			query.Set("headers", strings.Join(pairs, ","))
+		}
+		if b.Insecure {
+			query.Set("insecure", "true")
+		}
+		if b.SigningSecret != "" {
+			query.Set("signing_secret", b.SigningSecret)
+		}
+		if b.CAFile != "" {
+			query.Set("ca_file", b.CAFile)
+		}
+		if b.CertFile != "" {
+			query.Set("cert_file", b.CertFile)
+		}
+		if b.KeyFile != "" {
+			query.Set("key_file", b.KeyFile)
+		}
+		if b.ServerName != "" {
+			query.Set("server_name", b.ServerName)
+		}
+		u := url.URL{Scheme: "webhook", Host: host, Path: b.Path, RawQuery: query.Encode()}
+		return u.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported notifier type in config file: %s", b.Type)
+	}
+}
+
+// DomainBlock is one entry of the declarative "domains" list in a config
+// file, letting each monitored domain override its subdomains, DKIM
+// selectors, check interval, and DNS server independently.
+type DomainBlock struct {
+	Domain              string   `mapstructure:"domain"`
+	CustomSubdomains    []string `mapstructure:"custom_subdomains"`
+	CustomDkimSelectors []string `mapstructure:"custom_dkim_selectors"`
+	CheckInterval       string   `mapstructure:"check_interval"`
+	DNSServer           string   `mapstructure:"dns_server"`
+}
+
+// ToDomainConfig converts the block into a DomainConfig, defaulting
+// CheckInterval to defaultInterval when the block doesn't set one.
+func (b DomainBlock) ToDomainConfig(defaultInterval time.Duration) (DomainConfig, error) {
+	if b.Domain == "" {
+		return DomainConfig{}, errors.New("domains entry requires a domain")
+	}
+
+	interval := defaultInterval
+	if b.CheckInterval != "" {
+		duration, err := time.ParseDuration(b.CheckInterval)
+		if err != nil {
+			return DomainConfig{}, fmt.Errorf("invalid check_interval for domain %s: %w", b.Domain, err)
+		}
+		interval = duration
+	}
+
+	return DomainConfig{
+		Domain:              b.Domain,
+		CustomSubdomains:    b.CustomSubdomains,
+		CustomDkimSelectors: b.CustomDkimSelectors,
+		CheckInterval:       interval,
+		DNSServer:           b.DNSServer,
+	}, nil
+}
+
+// LoadConfigFromViper builds a Config from a layered viper instance. Viper
+// resolves precedence itself: CLI flags > environment variables > config
+// file > defaults. This is the configuration path for the dns-monitor CLI,
+// used both for its declarative config file/flags and for the legacy
+// plain-environment-variable setup, since viper's AutomaticEnv reads the
+// same env vars an empty viper instance would otherwise leave unset.
+func LoadConfigFromViper(v *viper.Viper) (*Config, error) {
+	domain := v.GetString("domain")
+	if domain == "" {
+		return &Config{}, errors.New("domain is required (set via --domain, DOMAIN, or the config file)")
+	}
+
+	notificationConfig, err := loadNotificationConfigFromViper(v)
+	if err != nil {
+		return &Config{}, fmt.Errorf("failed to load notification config: %w", err)
+	}
+
+	dnsServer := v.GetString("dns_server")
+	if dnsServer == "" {
+		dnsServer = "1.1.1.1:53"
+	}
+
+	dnsProtocol := v.GetString("dns_protocol")
+	if dnsProtocol == "" {
+		dnsProtocol = DNSProtocolUDP
+	}
+	if !IsValidDNSProtocol(dnsProtocol) {
+		return &Config{}, fmt.Errorf("invalid dns_protocol: %s (must be one of %v)", dnsProtocol, DNSProtocols)
+	}
+
+	checkInterval := v.GetDuration("check_interval")
+	if checkInterval == 0 {
+		checkInterval = 1 * time.Hour
+	}
+
+	propagationTimeout := v.GetDuration("propagation_timeout")
+	if propagationTimeout == 0 {
+		propagationTimeout = 10 * time.Minute
+	}
+
+	propagationInterval := v.GetDuration("propagation_interval")
+	if propagationInterval == 0 {
+		propagationInterval = 30 * time.Second
+	}
+
+	httpListenAddr := v.GetString("http_listen")
+	if httpListenAddr == "" {
+		httpListenAddr = ":8080"
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DisableKeepAlives: false,
+			IdleConnTimeout:   90 * time.Second,
+			MaxIdleConns:      100,
+			MaxConnsPerHost:   10,
+		},
+	}
+
+	dnsClient := new(dns.Client)
+
+	ignoredNames := v.GetStringSlice("ignored_names")
+	ignoredTargets := v.GetStringSlice("ignored_targets")
+	if err := ValidateGlobPatterns(ignoredNames); err != nil {
+		return &Config{}, fmt.Errorf("ignored_names: %w", err)
+	}
+	if err := ValidateGlobPatterns(ignoredTargets); err != nil {
+		return &Config{}, fmt.Errorf("ignored_targets: %w", err)
+	}
+
+	customSubdomains := v.GetStringSlice("custom_subdomains")
+	customDkimSelectors := v.GetStringSlice("custom_dkim_selectors")
+
+	domains, err := loadDomainsFromViper(v, domain, customSubdomains, customDkimSelectors, checkInterval)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	return &Config{
+		Domain:                domain,
+		CustomSubdomains:      customSubdomains,
+		CustomDkimSelectors:   customDkimSelectors,
+		DNSServer:             dnsServer,
+		DNSClient:             *dnsClient,
+		DNSProtocol:           dnsProtocol,
+		DNSTLSCAFile:          v.GetString("dns_tls_ca_file"),
+		DNSInsecureSkipVerify: v.GetBool("dns_insecure_skip_verify"),
+		DNSPinnedSPKI:         v.GetString("dns_pinned_spki"),
+		DNSSECEnabled:         v.GetBool("dnssec_enabled"),
+		DNSSECExpiryWindow:    v.GetDuration("dnssec_expiry_window"),
+		PropagationResolvers:  v.GetStringSlice("propagation_resolvers"),
+		PropagationTimeout:    propagationTimeout,
+		PropagationInterval:   propagationInterval,
+		CheckInterval:         checkInterval,
+		HTTPClient:            client,
+		NotificationConfig:    *notificationConfig,
+		NotifyOnErrors:        v.GetBool("notify_on_errors"),
+		HTTPListenAddr:        httpListenAddr,
+		MetricsAddr:           v.GetString("metrics_addr"),
+		ExpectedStateFile:     v.GetString("expected_state_file"),
+		Domains:               domains,
+		IgnoredNames:          ignoredNames,
+		IgnoredTargets:        ignoredTargets,
+		IgnoredTypes:          v.GetStringSlice("ignored_types"),
+		DryRunFilter:          v.GetBool("dry_run_filter"),
+	}, nil
+}
+
+// loadDomainsFromViper prefers a declarative "domains" list (config file)
+// over the DOMAINS/legacy single-domain env vars, mirroring
+// loadNotificationConfigFromViper's precedence for notifiers.
+func loadDomainsFromViper(v *viper.Viper, primaryDomain string, primarySubdomains, primarySelectors []string, primaryInterval time.Duration) ([]DomainConfig, error) {
+	var blocks []DomainBlock
+	if err := v.UnmarshalKey("domains", &blocks); err != nil {
+		return nil, fmt.Errorf("failed to parse domains config: %w", err)
+	}
+
+	if len(blocks) > 0 {
+		domains := make([]DomainConfig, 0, len(blocks))
+		for _, block := range blocks {
+			domainCfg, err := block.ToDomainConfig(primaryInterval)
+			if err != nil {
+				return nil, err
+			}
+			domains = append(domains, domainCfg)
+		}
+		return domains, nil
+	}
+
+	return buildDomainConfigs(primaryDomain, primarySubdomains, primarySelectors, primaryInterval)
+}
+
+// loadNotificationConfigFromViper prefers a declarative "notifiers" list over
+// the legacy single-provider environment scheme.
+func loadNotificationConfigFromViper(v *viper.Viper) (*NotificationConfig, error) {
+	var blocks []NotifierBlock
+	if err := v.UnmarshalKey("notifiers", &blocks); err != nil {
+		return nil, fmt.Errorf("failed to parse notifiers config: %w", err)
+	}
+
+	if len(blocks) > 0 {
+		urls := make([]string, 0, len(blocks))
+		for _, block := range blocks {
+			url, err := block.ToNotifierURL()
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, url)
+		}
+		return &NotificationConfig{
+			NotifierURLs:      urls,
+			TitleTag:          v.GetString("notification_title_tag"),
+			SkipTitle:         v.GetBool("notification_skip_title"),
+			HeartbeatInterval: v.GetDuration("heartbeat_interval"),
+		}, nil
+	}
+
+	return loadNotificationConfig()
+}
@@ -0,0 +1,242 @@
+// Package diff implements a semantic diff engine over two snapshots of DNS
+// records. Records are compared grouped by (Type, Name) RRset rather than as
+// a flat list, so an edited MX target or SPF string surfaces as a single
+// CHANGE and a pure TTL edit doesn't read as a delete-and-recreate.
+package diff
+
+import (
+	"dns-monitor/internal/common"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Verb classifies how one RRset, or one value within it, differs between
+// the old and new snapshot.
+type Verb string
+
+const (
+	Create    Verb = "CREATE"
+	Delete    Verb = "DELETE"
+	Change    Verb = "CHANGE"
+	Unchanged Verb = "UNCHANGED"
+)
+
+// Entry is one value-level comparison within a Hunk. Old and New hold the
+// zero DNSRecord on the side that doesn't apply to Verb. Note, when set,
+// overrides String with a structural diff line (e.g. from an SPF, DMARC, or
+// DKIM comparison) instead of the plain "old -> new" rendering.
+type Entry struct {
+	Verb Verb             `json:"verb"`
+	Old  common.DNSRecord `json:"old"`
+	New  common.DNSRecord `json:"new"`
+	Note string           `json:"note,omitempty"`
+}
+
+// String renders e as a single notification line.
+func (e Entry) String() string {
+	if e.Note != "" {
+		return e.Note
+	}
+
+	switch e.Verb {
+	case Create:
+		return e.New.Value
+	case Delete:
+		return e.Old.Value
+	case Change:
+		if e.Old.Value == e.New.Value {
+			return fmt.Sprintf("%s (ttl %d -> %d)", e.Old.Value, e.Old.TTL, e.New.TTL)
+		}
+		return fmt.Sprintf("%s -> %s", e.Old.Value, e.New.Value)
+	default:
+		return ""
+	}
+}
+
+// Hunk groups every Entry for one (Type, Name) RRset. Verb is the overall
+// classification for the RRset: CREATE if it's entirely new, DELETE if it's
+// entirely gone, CHANGE if any value or TTL differs, UNCHANGED if the RRset
+// is identical across both snapshots.
+type Hunk struct {
+	Type    string  `json:"type"`
+	Name    string  `json:"name"`
+	Verb    Verb    `json:"verb"`
+	Entries []Entry `json:"entries,omitempty"`
+}
+
+// String renders h as one notification section: a header naming the RRset
+// and its verb, followed by one indented line per Entry.
+func (h Hunk) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s %s", h.Verb, h.Type, h.Name)
+	for _, e := range h.Entries {
+		fmt.Fprintf(&b, "\n  - %s", e)
+	}
+	return b.String()
+}
+
+// Result is the outcome of diffing two DNS record snapshots, grouped by
+// RRset and sorted by Type then Name, so related TXT records (SPF, DMARC,
+// DKIM selectors) cluster together in rendered output.
+type Result struct {
+	Hunks []Hunk `json:"hunks"`
+}
+
+// Changed reports whether any Hunk differs between the two snapshots.
+func (r Result) Changed() bool {
+	return len(r.Changes()) > 0
+}
+
+// Changes returns the hunks that actually differ, in the same sorted order
+// as Hunks, skipping UNCHANGED ones.
+func (r Result) Changes() []Hunk {
+	var changed []Hunk
+	for _, h := range r.Hunks {
+		if h.Verb != Unchanged {
+			changed = append(changed, h)
+		}
+	}
+	return changed
+}
+
+type rrsetKey struct {
+	Type string
+	Name string
+}
+
+func groupByRRSet(records []common.DNSRecord) map[rrsetKey][]common.DNSRecord {
+	groups := make(map[rrsetKey][]common.DNSRecord)
+	for _, record := range records {
+		key := rrsetKey{Type: record.Type, Name: record.Name}
+		groups[key] = append(groups[key], record)
+	}
+	return groups
+}
+
+// Diff compares oldRecords and newRecords, grouped by (Type, Name) RRset,
+// and returns one Hunk per group.
+func Diff(oldRecords, newRecords []common.DNSRecord) Result {
+	oldGroups := groupByRRSet(oldRecords)
+	newGroups := groupByRRSet(newRecords)
+
+	seen := make(map[rrsetKey]bool, len(oldGroups))
+	var hunks []Hunk
+	for key, oldRecs := range oldGroups {
+		seen[key] = true
+		hunks = append(hunks, diffGroup(key, oldRecs, newGroups[key]))
+	}
+	for key, newRecs := range newGroups {
+		if !seen[key] {
+			hunks = append(hunks, diffGroup(key, nil, newRecs))
+		}
+	}
+
+	sort.Slice(hunks, func(i, j int) bool {
+		if hunks[i].Type != hunks[j].Type {
+			return hunks[i].Type < hunks[j].Type
+		}
+		return hunks[i].Name < hunks[j].Name
+	})
+
+	return Result{Hunks: hunks}
+}
+
+// diffGroup classifies and compares a single RRset. oldRecs and/or newRecs
+// may be empty, but not both.
+func diffGroup(key rrsetKey, oldRecs, newRecs []common.DNSRecord) Hunk {
+	hunk := Hunk{Type: key.Type, Name: key.Name}
+
+	switch {
+	case len(oldRecs) == 0:
+		hunk.Verb = Create
+		for _, rec := range newRecs {
+			hunk.Entries = append(hunk.Entries, Entry{Verb: Create, New: rec})
+		}
+		return hunk
+	case len(newRecs) == 0:
+		hunk.Verb = Delete
+		for _, rec := range oldRecs {
+			hunk.Entries = append(hunk.Entries, Entry{Verb: Delete, Old: rec})
+		}
+		return hunk
+	}
+
+	if key.Type == "TXT" && len(oldRecs) == 1 && len(newRecs) == 1 {
+		if notes, ok := diffSemanticTXT(oldRecs[0].Value, newRecs[0].Value); ok {
+			if len(notes) == 0 {
+				hunk.Verb = Unchanged
+				return hunk
+			}
+			hunk.Verb = Change
+			for _, note := range notes {
+				hunk.Entries = append(hunk.Entries, Entry{Verb: Change, Note: note})
+			}
+			return hunk
+		}
+	}
+
+	entries, changed := diffValues(oldRecs, newRecs)
+	hunk.Entries = entries
+	if changed {
+		hunk.Verb = Change
+	} else {
+		hunk.Verb = Unchanged
+	}
+	return hunk
+}
+
+// diffValues compares the values within one RRset. Values present
+// unchanged on both sides (same value, same TTL) are dropped; a value
+// present on both sides with a different TTL becomes a CHANGE entry.
+// Remaining old and new values are matched pairwise, in order, so an edited
+// value surfaces as a single CHANGE rather than a DELETE+CREATE pair; any
+// count mismatch left over becomes individual DELETE or CREATE entries.
+func diffValues(oldRecs, newRecs []common.DNSRecord) ([]Entry, bool) {
+	newByValue := make(map[string]common.DNSRecord, len(newRecs))
+	for _, rec := range newRecs {
+		newByValue[rec.Value] = rec
+	}
+	oldByValue := make(map[string]common.DNSRecord, len(oldRecs))
+	for _, rec := range oldRecs {
+		oldByValue[rec.Value] = rec
+	}
+
+	var entries []Entry
+	changed := false
+
+	var leftoverOld, leftoverNew []common.DNSRecord
+	for _, old := range oldRecs {
+		newRec, ok := newByValue[old.Value]
+		if !ok {
+			leftoverOld = append(leftoverOld, old)
+			continue
+		}
+		if newRec.TTL != old.TTL {
+			entries = append(entries, Entry{Verb: Change, Old: old, New: newRec})
+			changed = true
+		}
+	}
+	for _, newRec := range newRecs {
+		if _, ok := oldByValue[newRec.Value]; !ok {
+			leftoverNew = append(leftoverNew, newRec)
+		}
+	}
+
+	i := 0
+	for ; i < len(leftoverOld) && i < len(leftoverNew); i++ {
+		entries = append(entries, Entry{Verb: Change, Old: leftoverOld[i], New: leftoverNew[i]})
+		changed = true
+	}
+	for ; i < len(leftoverOld); i++ {
+		entries = append(entries, Entry{Verb: Delete, Old: leftoverOld[i]})
+		changed = true
+	}
+	for ; i < len(leftoverNew); i++ {
+		entries = append(entries, Entry{Verb: Create, New: leftoverNew[i]})
+		changed = true
+	}
+
+	sort.Slice(entries, func(a, b int) bool { return entries[a].String() < entries[b].String() })
+	return entries, changed
+}
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"dns-monitor/internal/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff_NoChanges(t *testing.T) {
+	records := []common.DNSRecord{
+		{Type: "MX", Name: "example.com.", Value: "10 mail.example.com.", TTL: 300},
+		{Type: "TXT", Name: "example.com.", Value: "some-other-txt-value", TTL: 300},
+	}
+
+	result := Diff(records, records)
+
+	assert.False(t, result.Changed())
+	assert.Empty(t, result.Changes())
+	for _, hunk := range result.Hunks {
+		assert.Equal(t, Unchanged, hunk.Verb)
+	}
+}
+
+func TestDiff_CreatedRRset(t *testing.T) {
+	oldRecords := []common.DNSRecord{
+		{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
+	}
+	newRecords := []common.DNSRecord{
+		{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
+		{Type: "TXT", Name: "example.com.", Value: "some-other-txt-value"},
+	}
+
+	result := Diff(oldRecords, newRecords)
+	changes := result.Changes()
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, Create, changes[0].Verb)
+	assert.Equal(t, "TXT", changes[0].Type)
+	assert.Equal(t, []Entry{{Verb: Create, New: newRecords[1]}}, changes[0].Entries)
+}
+
+func TestDiff_DeletedRRset(t *testing.T) {
+	oldRecords := []common.DNSRecord{
+		{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
+		{Type: "CNAME", Name: "www.example.com.", Value: "example.com."},
+	}
+	newRecords := []common.DNSRecord{
+		{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
+	}
+
+	result := Diff(oldRecords, newRecords)
+	changes := result.Changes()
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, Delete, changes[0].Verb)
+	assert.Equal(t, "CNAME", changes[0].Type)
+}
+
+func TestDiff_TTLOnlyChangeIsChangeNotDeleteAndCreate(t *testing.T) {
+	oldRecords := []common.DNSRecord{
+		{Type: "A", Name: "example.com.", Value: "1.2.3.4", TTL: 300},
+	}
+	newRecords := []common.DNSRecord{
+		{Type: "A", Name: "example.com.", Value: "1.2.3.4", TTL: 60},
+	}
+
+	result := Diff(oldRecords, newRecords)
+	changes := result.Changes()
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, Change, changes[0].Verb)
+	assert.Equal(t, []Entry{{Verb: Change, Old: oldRecords[0], New: newRecords[0]}}, changes[0].Entries)
+	assert.Equal(t, "1.2.3.4 (ttl 300 -> 60)", changes[0].Entries[0].String())
+}
+
+func TestDiff_EditedValuePairsAsSingleChange(t *testing.T) {
+	oldRecords := []common.DNSRecord{
+		{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
+	}
+	newRecords := []common.DNSRecord{
+		{Type: "MX", Name: "example.com.", Value: "20 mail2.example.com."},
+	}
+
+	result := Diff(oldRecords, newRecords)
+	changes := result.Changes()
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, Change, changes[0].Verb)
+	assert.Equal(t, []Entry{{Verb: Change, Old: oldRecords[0], New: newRecords[0]}}, changes[0].Entries)
+	assert.Equal(t, "10 mail.example.com. -> 20 mail2.example.com.", changes[0].Entries[0].String())
+}
+
+func TestDiff_AsymmetricValueCountBecomesCreateOrDelete(t *testing.T) {
+	oldRecords := []common.DNSRecord{
+		{Type: "A", Name: "example.com.", Value: "1.2.3.4"},
+	}
+	newRecords := []common.DNSRecord{
+		{Type: "A", Name: "example.com.", Value: "1.2.3.4"},
+		{Type: "A", Name: "example.com.", Value: "5.6.7.8"},
+	}
+
+	result := Diff(oldRecords, newRecords)
+	changes := result.Changes()
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, Change, changes[0].Verb)
+	assert.Equal(t, []Entry{{Verb: Create, New: newRecords[1]}}, changes[0].Entries)
+}
+
+func TestDiff_HunksSortedByTypeThenName(t *testing.T) {
+	oldRecords := []common.DNSRecord{}
+	newRecords := []common.DNSRecord{
+		{Type: "TXT", Name: "_dmarc.example.com.", Value: "v=DMARC1; p=reject;"},
+		{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
+		{Type: "TXT", Name: "selector._domainkey.example.com.", Value: "v=DKIM1; k=rsa; p=abc"},
+		{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
+	}
+
+	result := Diff(oldRecords, newRecords)
+
+	var order []string
+	for _, hunk := range result.Hunks {
+		order = append(order, hunk.Type+":"+hunk.Name)
+	}
+	assert.Equal(t, []string{
+		"MX:example.com.",
+		"TXT:_dmarc.example.com.",
+		"TXT:example.com.",
+		"TXT:selector._domainkey.example.com.",
+	}, order)
+}
+
+func TestHunk_String(t *testing.T) {
+	hunk := Hunk{
+		Type: "MX",
+		Name: "example.com.",
+		Verb: Change,
+		Entries: []Entry{
+			{Verb: Change, Old: common.DNSRecord{Value: "10 mail.example.com."}, New: common.DNSRecord{Value: "20 mail2.example.com."}},
+		},
+	}
+
+	assert.Equal(t, "CHANGE: MX example.com.\n  - 10 mail.example.com. -> 20 mail2.example.com.", hunk.String())
+}
@@ -0,0 +1,249 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// classifyTXT identifies the semantic kind of a TXT record value, so
+// diffGroup can compare it structurally instead of as an opaque string.
+// Values that don't match a known kind return "", and the caller falls back
+// to the plain value diff.
+func classifyTXT(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case strings.HasPrefix(normalized, "v=spf1"):
+		return "spf"
+	case strings.HasPrefix(normalized, "v=dmarc1"):
+		return "dmarc"
+	case strings.HasPrefix(normalized, "v=dkim1"):
+		return "dkim"
+	default:
+		return ""
+	}
+}
+
+// diffSemanticTXT returns a structured diff for oldValue/newValue when both
+// are the same recognized TXT kind (SPF, DMARC, or DKIM), along with whether
+// a semantic diff applies. When it doesn't, the caller should fall back to
+// the plain value diff.
+func diffSemanticTXT(oldValue, newValue string) ([]string, bool) {
+	oldKind := classifyTXT(oldValue)
+	newKind := classifyTXT(newValue)
+	if oldKind == "" || oldKind != newKind {
+		return nil, false
+	}
+
+	switch oldKind {
+	case "spf":
+		return diffSPF(oldValue, newValue), true
+	case "dmarc":
+		return diffDMARC(oldValue, newValue), true
+	case "dkim":
+		return diffDKIM(oldValue, newValue), true
+	default:
+		return nil, false
+	}
+}
+
+// spfMechanism is one whitespace-separated term of an SPF record, e.g.
+// "-all" or "include:_spf.google.com".
+type spfMechanism struct {
+	Qualifier string // "+", "-", "~", or "?" (defaults to "+")
+	Type      string // ip4, ip6, a, mx, include, exists, redirect, exp, ptr, all
+	Value     string // text after ":" or "=", if any
+}
+
+func parseSPFMechanisms(record string) []spfMechanism {
+	var mechanisms []spfMechanism
+	for _, field := range strings.Fields(record) {
+		if strings.EqualFold(field, "v=spf1") {
+			continue
+		}
+		mechanisms = append(mechanisms, parseSPFMechanism(field))
+	}
+	return mechanisms
+}
+
+func parseSPFMechanism(token string) spfMechanism {
+	qualifier := "+"
+	if len(token) > 0 {
+		switch token[0] {
+		case '+', '-', '~', '?':
+			qualifier = string(token[0])
+			token = token[1:]
+		}
+	}
+
+	typ, value := token, ""
+	if idx := strings.IndexAny(token, ":="); idx >= 0 {
+		typ, value = token[:idx], token[idx+1:]
+	}
+
+	return spfMechanism{Qualifier: qualifier, Type: typ, Value: value}
+}
+
+func (m spfMechanism) key() string {
+	return m.Type + ":" + m.Value
+}
+
+// sortedKeys returns byKey's keys in sorted order, so diffSPF's added/removed
+// lines come out in a stable order instead of Go's randomized map iteration.
+func sortedKeys(byKey map[string]spfMechanism) []string {
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffSPF compares two SPF records as a set of mechanisms plus the final
+// "all" qualifier, so reordering or whitespace changes produce no noise
+// while added/removed mechanisms and relaxed/tightened "all" qualifiers do.
+func diffSPF(oldValue, newValue string) []string {
+	oldMechanisms := parseSPFMechanisms(oldValue)
+	newMechanisms := parseSPFMechanisms(newValue)
+
+	var oldAll, newAll *spfMechanism
+	oldByKey := make(map[string]spfMechanism)
+	newByKey := make(map[string]spfMechanism)
+
+	for _, m := range oldMechanisms {
+		if m.Type == "all" {
+			mCopy := m
+			oldAll = &mCopy
+			continue
+		}
+		oldByKey[m.key()] = m
+	}
+	for _, m := range newMechanisms {
+		if m.Type == "all" {
+			mCopy := m
+			newAll = &mCopy
+			continue
+		}
+		newByKey[m.key()] = m
+	}
+
+	var changes []string
+	for _, key := range sortedKeys(newByKey) {
+		if _, exists := oldByKey[key]; !exists {
+			m := newByKey[key]
+			changes = append(changes, fmt.Sprintf("SPF %s added: %s", m.Type, m.Value))
+		}
+	}
+	for _, key := range sortedKeys(oldByKey) {
+		if _, exists := newByKey[key]; !exists {
+			m := oldByKey[key]
+			changes = append(changes, fmt.Sprintf("SPF %s removed: %s", m.Type, m.Value))
+		}
+	}
+
+	if oldAll != nil && newAll != nil && oldAll.Qualifier != newAll.Qualifier {
+		changes = append(changes, fmt.Sprintf("SPF all qualifier changed: %sall -> %sall", oldAll.Qualifier, newAll.Qualifier))
+	}
+
+	return changes
+}
+
+// parseTagList parses a ";"-separated list of "k=v" tags, as used by both
+// DMARC and DKIM TXT records.
+func parseTagList(record string) map[string]string {
+	tags := make(map[string]string)
+	for _, part := range strings.Split(record, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// dmarcPolicyRank orders DMARC policies by strictness, so a change can be
+// flagged as a downgrade rather than just "changed".
+func dmarcPolicyRank(policy string) int {
+	switch strings.ToLower(policy) {
+	case "reject":
+		return 3
+	case "quarantine":
+		return 2
+	case "none":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// diffDMARC flags policy downgrades (p, sp), report address changes
+// (rua, ruf), and pct decreases as high-severity, since they weaken or
+// silence DMARC enforcement.
+func diffDMARC(oldValue, newValue string) []string {
+	oldTags := parseTagList(oldValue)
+	newTags := parseTagList(newValue)
+
+	var changes []string
+	changes = append(changes, diffDMARCPolicy("p", oldTags, newTags)...)
+	changes = append(changes, diffDMARCPolicy("sp", oldTags, newTags)...)
+
+	if oldTags["rua"] != newTags["rua"] {
+		changes = append(changes, fmt.Sprintf("DMARC rua changed: %s -> %s", oldTags["rua"], newTags["rua"]))
+	}
+	if oldTags["ruf"] != newTags["ruf"] {
+		changes = append(changes, fmt.Sprintf("DMARC ruf changed: %s -> %s", oldTags["ruf"], newTags["ruf"]))
+	}
+
+	if oldTags["pct"] != newTags["pct"] {
+		oldPct, oldErr := strconv.Atoi(oldTags["pct"])
+		newPct, newErr := strconv.Atoi(newTags["pct"])
+		if oldErr == nil && newErr == nil && newPct < oldPct {
+			changes = append(changes, fmt.Sprintf("[HIGH] DMARC pct decreased: %d -> %d", oldPct, newPct))
+		} else {
+			changes = append(changes, fmt.Sprintf("DMARC pct changed: %s -> %s", oldTags["pct"], newTags["pct"]))
+		}
+	}
+
+	return changes
+}
+
+func diffDMARCPolicy(tag string, oldTags, newTags map[string]string) []string {
+	oldPolicy, newPolicy := oldTags[tag], newTags[tag]
+	if oldPolicy == newPolicy {
+		return nil
+	}
+	if dmarcPolicyRank(newPolicy) < dmarcPolicyRank(oldPolicy) {
+		return []string{fmt.Sprintf("[HIGH] DMARC %s downgraded: %s -> %s", tag, oldPolicy, newPolicy)}
+	}
+	return []string{fmt.Sprintf("DMARC %s changed: %s -> %s", tag, oldPolicy, newPolicy)}
+}
+
+// diffDKIM flags key rotation, revocation, and signing algorithm changes.
+func diffDKIM(oldValue, newValue string) []string {
+	oldTags := parseTagList(oldValue)
+	newTags := parseTagList(newValue)
+
+	var changes []string
+
+	if oldTags["p"] != newTags["p"] {
+		if newTags["p"] == "" {
+			changes = append(changes, "[HIGH] DKIM key revoked: p is empty")
+		} else if oldTags["p"] == "" {
+			changes = append(changes, "DKIM key published: p is now set")
+		} else {
+			changes = append(changes, "DKIM key rotated: p changed")
+		}
+	}
+
+	if oldTags["k"] != newTags["k"] {
+		changes = append(changes, fmt.Sprintf("DKIM algorithm changed: %s -> %s", oldTags["k"], newTags["k"]))
+	}
+
+	return changes
+}
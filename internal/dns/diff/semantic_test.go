@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"dns-monitor/internal/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff_MultiValueTXTGroupFallsBackToOpaqueDiff(t *testing.T) {
+	// Two TXT records sharing the same Type:Name group aren't a single SPF
+	// record, so they fall back to the plain value diff rather than
+	// semantic comparison.
+	oldRecords := []common.DNSRecord{
+		{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
+		{Type: "TXT", Name: "example.com.", Value: "some-other-txt-value"},
+	}
+	newRecords := []common.DNSRecord{
+		{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
+		{Type: "TXT", Name: "example.com.", Value: "some-other-txt-value-changed"},
+	}
+
+	result := Diff(oldRecords, newRecords)
+
+	assert.Len(t, result.Hunks, 1)
+	hunk := result.Hunks[0]
+	assert.Equal(t, Change, hunk.Verb)
+	assert.Equal(t, []Entry{
+		{Verb: Change, Old: common.DNSRecord{Type: "TXT", Name: "example.com.", Value: "some-other-txt-value"}, New: common.DNSRecord{Type: "TXT", Name: "example.com.", Value: "some-other-txt-value-changed"}},
+	}, hunk.Entries)
+}
+
+func TestClassifyTXT(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"v=spf1 -all", "spf"},
+		{"v=DMARC1; p=reject;", "dmarc"},
+		{"v=DKIM1; k=rsa; p=abc123", "dkim"},
+		{"some-unrelated-value", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, classifyTXT(tt.value))
+	}
+}
+
+func TestDiffSPF_NoSemanticChange(t *testing.T) {
+	changes := diffSPF("v=spf1 include:_spf.google.com -all", "v=spf1  include:_spf.google.com   -all")
+	assert.Empty(t, changes)
+}
+
+func TestDiffSPF_MechanismAddedAndRemoved(t *testing.T) {
+	// diffSPF sorts mechanism keys before emitting changes, so this order is
+	// deterministic across runs rather than incidentally stable.
+	changes := diffSPF("v=spf1 ip4:192.0.2.0/24 -all", "v=spf1 include:_spf.google.com -all")
+
+	assert.Equal(t, []string{
+		"SPF include added: _spf.google.com",
+		"SPF ip4 removed: 192.0.2.0/24",
+	}, changes)
+}
+
+func TestDiffSPF_MultipleMechanismsAddedInSortedOrder(t *testing.T) {
+	changes := diffSPF("v=spf1 -all", "v=spf1 mx include:_spf.google.com a -all")
+
+	assert.Equal(t, []string{
+		"SPF a added: ",
+		"SPF include added: _spf.google.com",
+		"SPF mx added: ",
+	}, changes)
+}
+
+func TestDiffSPF_AllQualifierChanged(t *testing.T) {
+	changes := diffSPF("v=spf1 -all", "v=spf1 ~all")
+	assert.Equal(t, []string{"SPF all qualifier changed: -all -> ~all"}, changes)
+}
+
+func TestDiffDMARC_PolicyDowngrade(t *testing.T) {
+	changes := diffDMARC("v=DMARC1; p=reject; rua=mailto:a@example.com", "v=DMARC1; p=none; rua=mailto:a@example.com")
+	assert.Equal(t, []string{"[HIGH] DMARC p downgraded: reject -> none"}, changes)
+}
+
+func TestDiffDMARC_PolicyUpgradeIsNotHighSeverity(t *testing.T) {
+	changes := diffDMARC("v=DMARC1; p=none", "v=DMARC1; p=reject")
+	assert.Equal(t, []string{"DMARC p changed: none -> reject"}, changes)
+}
+
+func TestDiffDMARC_PctDecrease(t *testing.T) {
+	changes := diffDMARC("v=DMARC1; p=reject; pct=100", "v=DMARC1; p=reject; pct=50")
+	assert.Equal(t, []string{"[HIGH] DMARC pct decreased: 100 -> 50"}, changes)
+}
+
+func TestDiffDMARC_RuaChanged(t *testing.T) {
+	changes := diffDMARC("v=DMARC1; p=reject; rua=mailto:old@example.com", "v=DMARC1; p=reject; rua=mailto:new@example.com")
+	assert.Equal(t, []string{"DMARC rua changed: mailto:old@example.com -> mailto:new@example.com"}, changes)
+}
+
+func TestDiffDKIM_KeyRevoked(t *testing.T) {
+	changes := diffDKIM("v=DKIM1; k=rsa; p=abc123", "v=DKIM1; k=rsa; p=")
+	assert.Equal(t, []string{"[HIGH] DKIM key revoked: p is empty"}, changes)
+}
+
+func TestDiffDKIM_KeyRotated(t *testing.T) {
+	changes := diffDKIM("v=DKIM1; k=rsa; p=abc123", "v=DKIM1; k=rsa; p=def456")
+	assert.Equal(t, []string{"DKIM key rotated: p changed"}, changes)
+}
+
+func TestDiffDKIM_AlgorithmChanged(t *testing.T) {
+	changes := diffDKIM("v=DKIM1; k=rsa; p=abc123", "v=DKIM1; k=ed25519; p=abc123")
+	assert.Equal(t, []string{"DKIM algorithm changed: rsa -> ed25519"}, changes)
+}
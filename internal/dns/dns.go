@@ -3,9 +3,9 @@ package dns
 import (
 	"context"
 	"dns-monitor/internal/common"
+	"dns-monitor/internal/metrics"
 	"fmt"
 	"log"
-	"sort"
 	"strings"
 	"time"
 
@@ -17,9 +17,21 @@ const (
 	initialDelay = 500 * time.Millisecond
 )
 
-// FetchDNSRecords fetches DNS records for a domain from a specific DNS server.
-func FetchDNSRecords(ctx context.Context, config common.Config) ([]common.DNSRecord, error) {
+// FetchDNSRecords fetches DNS records for one monitored domain. config
+// carries the shared DNS transport settings (server, protocol, TLS, HTTP
+// client), while domainCfg scopes the query to a single domain from
+// config.Domains, optionally overriding the DNS server for that domain.
+// mtr, when non-nil, records per-query duration and retry metrics. expected,
+// when non-empty, is a desired-state baseline (see LoadExpectedState): the
+// names it lists replace the usual fixed subdomain/selector set, so the
+// check queries exactly the names the baseline cares about.
+//
+// The second return value holds every successful raw response, so a caller
+// with config.DNSSECEnabled set can feed them to CheckDNSSEC; it's nil when
+// DNSSEC checking is disabled.
+func FetchDNSRecords(ctx context.Context, config common.Config, domainCfg common.DomainConfig, mtr *metrics.Metrics, expected []common.DNSRecord) ([]common.DNSRecord, []*dns.Msg, error) {
 	var allRecords []common.DNSRecord
+	var responses []*dns.Msg
 
 	// Record types to check - focusing on email-related records.
 	recordTypes := []uint16{
@@ -29,13 +41,24 @@ func FetchDNSRecords(ctx context.Context, config common.Config) ([]common.DNSRec
 		dns.TypeA,
 	}
 
+	// In desired-state mode, a real zone file routinely declares types this
+	// fixed set never queries (SOA/NS at the apex, AAAA/SRV/CAA anywhere
+	// else). Without these, DetectDrift would report every one of them
+	// MISSING forever, so union in whatever types the baseline actually uses.
+	recordTypes = append(recordTypes, expectedRecordTypes(expected, recordTypes)...)
+
 	// Generate the list of domains to check.
-	domainsToCheck := generateDomainsToCheck(config)
+	domainsToCheck := generateDomainsToCheck(domainCfg, expected)
+
+	effectiveConfig := config
+	if domainCfg.DNSServer != "" {
+		effectiveConfig.DNSServer = domainCfg.DNSServer
+	}
 
 	// Iterate through each domain and record type.
 	for _, domainName := range domainsToCheck {
 		for _, recordType := range recordTypes {
-			r, err := queryDNS(ctx, domainName, recordType, config)
+			r, err := queryDNS(ctx, domainName, recordType, effectiveConfig, mtr)
 			if err != nil {
 				log.Printf("Error querying %s for %s: %v", domainName, dns.TypeToString[recordType], err)
 				continue
@@ -44,19 +67,104 @@ func FetchDNSRecords(ctx context.Context, config common.Config) ([]common.DNSRec
 				continue
 			}
 
+			if config.DNSSECEnabled {
+				responses = append(responses, r)
+			}
+
 			for _, ans := range r.Answer {
-				record := parseDNSRecord(ans, recordType)
+				// ans.Header().Rrtype, not the outer recordType: with the DO bit
+				// set, r.Answer can carry an RRSIG riding alongside the queried
+				// type, and parseDNSRecord needs its actual type to label it.
+				rrtype := ans.Header().Rrtype
+				if isDNSSECOnlyType(rrtype) {
+					// DNSSEC proof records (RRSIG/DNSKEY/DS/NSEC/NSEC3) carry a
+					// live signature/expiry in their Value (see parseDNSRecord),
+					// so every routine re-sign would otherwise show up as a
+					// spurious change here. CheckDNSSEC reads them straight out
+					// of responses instead; they never belong in allRecords.
+					continue
+				}
+				record := parseDNSRecord(ans, rrtype)
 				allRecords = append(allRecords, record)
 			}
 		}
 	}
 
-	return allRecords, nil
+	if config.DNSSECEnabled {
+		// DNSKEY and DS live at the zone apex, not at every subdomain/selector
+		// queried above, so they get their own pass scoped to domainCfg.Domain
+		// rather than multiplying every name in domainsToCheck by two more
+		// queries that would almost always come back empty.
+		for _, recordType := range []uint16{dns.TypeDNSKEY, dns.TypeDS} {
+			r, err := queryDNS(ctx, domainCfg.Domain, recordType, effectiveConfig, mtr)
+			if err != nil {
+				log.Printf("Error querying %s for %s: %v", domainCfg.Domain, dns.TypeToString[recordType], err)
+				continue
+			}
+			if r.Rcode != dns.RcodeSuccess {
+				continue
+			}
+
+			responses = append(responses, r)
+		}
+	}
+
+	return allRecords, responses, nil
+}
+
+// isDNSSECOnlyType reports whether rrtype is one of the DNSSEC proof record
+// types (RRSIG, DNSKEY, DS, NSEC, NSEC3). These are CheckDNSSEC's input, read
+// straight from the raw responses, and must never reach allRecords: several
+// of them embed a live signature/expiry timestamp, so routine re-signing
+// would otherwise look like a DNS change on every check.
+func isDNSSECOnlyType(rrtype uint16) bool {
+	switch rrtype {
+	case dns.TypeRRSIG, dns.TypeDNSKEY, dns.TypeDS, dns.TypeNSEC, dns.TypeNSEC3:
+		return true
+	default:
+		return false
+	}
+}
+
+// expectedRecordTypes returns the distinct record types named in expected
+// that aren't already in existing, translated from their DNSRecord.Type
+// string (e.g. "SOA") to the uint16 FetchDNSRecords queries with. Types it
+// can't recognize via dns.StringToType are skipped rather than erroring,
+// since expected is user-authored data and an unknown type here shouldn't
+// break the rest of the check.
+func expectedRecordTypes(expected []common.DNSRecord, existing []uint16) []uint16 {
+	have := make(map[uint16]bool, len(existing))
+	for _, t := range existing {
+		have[t] = true
+	}
+
+	var extra []uint16
+	for _, record := range expected {
+		rrtype, ok := dns.StringToType[record.Type]
+		if !ok || have[rrtype] {
+			continue
+		}
+		have[rrtype] = true
+		extra = append(extra, rrtype)
+	}
+
+	return extra
 }
 
-// generateDomainsToCheck builds the list of domains and subdomains to query.
-func generateDomainsToCheck(config common.Config) []string {
-	domain := config.Domain
+// generateDomainsToCheck builds the list of domains and subdomains to query
+// for one monitored domain. When expected is non-empty (desired-state mode),
+// the names it lists are used as-is instead of the fixed subdomain/selector
+// set, so the check is scoped to exactly what the baseline declares.
+func generateDomainsToCheck(domainCfg common.DomainConfig, expected []common.DNSRecord) []string {
+	if len(expected) > 0 {
+		names := make([]string, 0, len(expected))
+		for _, record := range expected {
+			names = append(names, strings.TrimSuffix(record.Name, "."))
+		}
+		return deduplicate(names)
+	}
+
+	domain := domainCfg.Domain
 	domains := []string{
 		domain,                               // Main domain
 		fmt.Sprintf("_dmarc.%s", domain),     // DMARC policy
@@ -65,27 +173,60 @@ func generateDomainsToCheck(config common.Config) []string {
 	}
 
 	// Add custom DKIM selectors.
-	for _, selector := range config.CustomDkimSelectors {
+	for _, selector := range domainCfg.CustomDkimSelectors {
 		domains = append(domains, fmt.Sprintf("%s._domainkey.%s", selector, domain))
 	}
 
-	// Add any custom domains that were provided at runtime
-	domains = append(domains, config.CustomDomains...)
+	// Add any custom subdomains that were provided at runtime
+	for _, subdomain := range domainCfg.CustomSubdomains {
+		domains = append(domains, fmt.Sprintf("%s.%s", subdomain, domain))
+	}
 
-	return domains
+	return deduplicate(domains)
 }
 
-// queryDNS sends a DNS query for the given domain name and record type, with exponential backoff retries.
-func queryDNS(ctx context.Context, domainName string, recordType uint16, config common.Config) (*dns.Msg, error) {
-	var resp *dns.Msg
+// deduplicate removes duplicate strings from a slice while preserving the
+// order of first occurrence.
+func deduplicate(values []string) []string {
+	if values == nil {
+		return nil
+	}
+
+	var result []string
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+
+	return result
+}
+
+// queryDNS sends a DNS query for the given domain name and record type, with
+// exponential backoff retries. The transport used to reach config.DNSServer
+// is selected by common.NewTransport, from either a URL-style scheme on
+// config.DNSServer or config.DNSProtocol. mtr, when non-nil, observes the
+// query's duration and each retry attempt's outcome.
+func queryDNS(ctx context.Context, domainName string, recordType uint16, config common.Config, mtr *metrics.Metrics) (*dns.Msg, error) {
+	transport, err := common.NewTransport(config, config.DNSServer)
+	if err != nil {
+		return nil, err
+	}
 
+	var resp *dns.Msg
 	operation := func() error {
 		m := new(dns.Msg)
 		m.SetQuestion(dns.Fqdn(domainName), recordType)
 		m.RecursionDesired = true
+		if config.DNSSECEnabled {
+			m.SetEdns0(4096, true)
+		}
 
 		var err error
-		resp, _, err = config.DNSClient.Exchange(m, config.DNSServer)
+		resp, err = transport.Exchange(ctx, m)
 		if err != nil {
 			return fmt.Errorf("DNS query failed: %w", err)
 		}
@@ -95,8 +236,17 @@ func queryDNS(ctx context.Context, domainName string, recordType uint16, config
 		return nil
 	}
 
+	var onAttempt func(success bool)
+	if mtr != nil {
+		onAttempt = mtr.RetryHook()
+	}
+
+	start := time.Now()
 	// Retry with exponential backoff.
-	err := common.RetryWithExponentialBackoff(ctx, retries, initialDelay, operation)
+	err = common.RetryWithExponentialBackoffHook(ctx, retries, initialDelay, operation, onAttempt)
+	if mtr != nil {
+		mtr.RecordQueryDuration(dns.TypeToString[recordType], time.Since(start).Seconds())
+	}
 	return resp, err
 }
 
@@ -119,6 +269,16 @@ func parseDNSRecord(rr dns.RR, recordType uint16) common.DNSRecord {
 		record.Value = v.A.String()
 	case *dns.AAAA:
 		record.Value = v.AAAA.String()
+	case *dns.RRSIG:
+		record.Value = fmt.Sprintf("%s %d %s exp=%d", dns.TypeToString[v.TypeCovered], v.KeyTag, v.SignerName, v.Expiration)
+	case *dns.DNSKEY:
+		record.Value = fmt.Sprintf("flags=%d proto=%d alg=%d keytag=%d", v.Flags, v.Protocol, v.Algorithm, v.KeyTag())
+	case *dns.DS:
+		record.Value = fmt.Sprintf("%d %d %d %s", v.KeyTag, v.Algorithm, v.DigestType, v.Digest)
+	case *dns.NSEC:
+		record.Value = fmt.Sprintf("%s %s", v.NextDomain, strings.Join(typeBitMapStrings(v.TypeBitMap), " "))
+	case *dns.NSEC3:
+		record.Value = fmt.Sprintf("%d %d %d %s %s %s", v.Hash, v.Flags, v.Iterations, v.Salt, v.NextDomain, strings.Join(typeBitMapStrings(v.TypeBitMap), " "))
 	default:
 		record.Value = rr.String()
 	}
@@ -126,20 +286,14 @@ func parseDNSRecord(rr dns.RR, recordType uint16) common.DNSRecord {
 	return record
 }
 
-// DetectChanges identifies differences between two sets of DNS records
-func DetectChanges(oldRecords, newRecords []common.DNSRecord) []string {
-	// Build record maps
-	oldRecordMap := buildRecordMap(oldRecords)
-	newRecordMap := buildRecordMap(newRecords)
-
-	// Collect all changes
-	var changes []string
-	changes = append(changes, detectAddedAndModifiedRecords(oldRecordMap, newRecordMap)...)
-	changes = append(changes, detectDeletedRecords(oldRecordMap, newRecordMap)...)
-
-	// Sort changes for consistent output
-	sort.Strings(changes)
-	return changes
+// typeBitMapStrings renders an NSEC/NSEC3 type bitmap as the record type
+// names it covers, e.g. []uint16{1, 15, 16} -> []string{"A", "MX", "TXT"}.
+func typeBitMapStrings(types []uint16) []string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = dns.TypeToString[t]
+	}
+	return names
 }
 
 // buildRecordMap creates a map of records grouped by Type:Name
@@ -158,88 +312,3 @@ func buildRecordMap(records []common.DNSRecord) map[string][]common.DNSRecord {
 func formatRecordKey(recordType, recordName string) string {
 	return fmt.Sprintf("%s:%s", recordType, recordName)
 }
-
-// detectAddedAndModifiedRecords finds new records and changed values
-func detectAddedAndModifiedRecords(oldMap, newMap map[string][]common.DNSRecord) []string {
-	var changes []string
-
-	for key, newRecs := range newMap {
-		oldRecs, exists := oldMap[key]
-
-		if !exists {
-			// All records in this group are new
-			changes = append(changes, formatNewRecordChanges(newRecs)...)
-		} else {
-			// Check for added or removed values within this group
-			changes = append(changes, detectValueChanges(oldRecs, newRecs)...)
-		}
-	}
-
-	return changes
-}
-
-// formatNewRecordChanges creates change messages for newly added records
-func formatNewRecordChanges(records []common.DNSRecord) []string {
-	var changes []string
-
-	for _, record := range records {
-		changes = append(changes, fmt.Sprintf("NEW: %s %s -> %s",
-			record.Type, record.Name, record.Value))
-	}
-
-	return changes
-}
-
-// detectValueChanges compares values within the same Type:Name record group
-func detectValueChanges(oldRecs, newRecs []common.DNSRecord) []string {
-	var changes []string
-
-	// Create value lookup maps
-	oldValueMap := createValueMap(oldRecs)
-	newValueMap := createValueMap(newRecs)
-
-	// Find added values
-	for _, newRecord := range newRecs {
-		if !oldValueMap[newRecord.Value] {
-			changes = append(changes, fmt.Sprintf("ADDED: %s %s -> %s",
-				newRecord.Type, newRecord.Name, newRecord.Value))
-		}
-	}
-
-	// Find removed values
-	for _, oldRecord := range oldRecs {
-		if !newValueMap[oldRecord.Value] {
-			changes = append(changes, fmt.Sprintf("REMOVED: %s %s -> %s",
-				oldRecord.Type, oldRecord.Name, oldRecord.Value))
-		}
-	}
-
-	return changes
-}
-
-// createValueMap creates a map of values for fast lookup
-func createValueMap(records []common.DNSRecord) map[string]bool {
-	valueMap := make(map[string]bool)
-
-	for _, record := range records {
-		valueMap[record.Value] = true
-	}
-
-	return valueMap
-}
-
-// detectDeletedRecords finds record groups that no longer exist
-func detectDeletedRecords(oldMap, newMap map[string][]common.DNSRecord) []string {
-	var changes []string
-
-	for key, oldRecs := range oldMap {
-		if _, exists := newMap[key]; !exists {
-			for _, record := range oldRecs {
-				changes = append(changes, fmt.Sprintf("DELETED: %s %s -> %s",
-					record.Type, record.Name, record.Value))
-			}
-		}
-	}
-
-	return changes
-}
@@ -14,13 +14,13 @@ import (
 func TestGenerateDomainsToCheck(t *testing.T) {
 	tests := []struct {
 		name             string
-		config           common.Config
+		config           common.DomainConfig
 		expectedDomains  []string
 		notExpectedCount int // used to make sure there are no duplicates
 	}{
 		{
 			name: "Basic domain with no custom selectors or subdomains",
-			config: common.Config{
+			config: common.DomainConfig{
 				Domain:              "example.com",
 				CustomDkimSelectors: []string{},
 				CustomSubdomains:    []string{},
@@ -35,7 +35,7 @@ func TestGenerateDomainsToCheck(t *testing.T) {
 		},
 		{
 			name: "Domain with custom DKIM selectors",
-			config: common.Config{
+			config: common.DomainConfig{
 				Domain:              "example.com",
 				CustomDkimSelectors: []string{"selector1", "selector2"},
 				CustomSubdomains:    []string{},
@@ -52,7 +52,7 @@ func TestGenerateDomainsToCheck(t *testing.T) {
 		},
 		{
 			name: "Domain with custom subdomains",
-			config: common.Config{
+			config: common.DomainConfig{
 				Domain:              "example.com",
 				CustomDkimSelectors: []string{},
 				CustomSubdomains:    []string{"mail", "blog"},
@@ -69,7 +69,7 @@ func TestGenerateDomainsToCheck(t *testing.T) {
 		},
 		{
 			name: "Domain with duplicated subdomains",
-			config: common.Config{
+			config: common.DomainConfig{
 				Domain:              "example.com",
 				CustomDkimSelectors: []string{"selector1"},
 				CustomSubdomains:    []string{"www", "www"}, // Duplicate www
@@ -87,7 +87,7 @@ func TestGenerateDomainsToCheck(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := generateDomainsToCheck(tt.config)
+			result := generateDomainsToCheck(tt.config, nil)
 
 			// Check that all expected domains are present
 			for _, expected := range tt.expectedDomains {
@@ -107,6 +107,34 @@ func TestGenerateDomainsToCheck(t *testing.T) {
 	}
 }
 
+func TestGenerateDomainsToCheck_ExpectedStateOverridesFixedSet(t *testing.T) {
+	expected := []common.DNSRecord{
+		{Type: "A", Name: "example.com."},
+		{Type: "MX", Name: "example.com."},
+		{Type: "TXT", Name: "_dmarc.example.com."},
+	}
+
+	result := generateDomainsToCheck(common.DomainConfig{Domain: "example.com"}, expected)
+
+	assert.ElementsMatch(t, []string{"example.com", "_dmarc.example.com"}, result)
+}
+
+func TestExpectedRecordTypes(t *testing.T) {
+	existing := []uint16{dns.TypeMX, dns.TypeTXT, dns.TypeCNAME, dns.TypeA}
+
+	expected := []common.DNSRecord{
+		{Type: "SOA", Name: "example.com."},
+		{Type: "NS", Name: "example.com."},
+		{Type: "AAAA", Name: "www.example.com."},
+		{Type: "A", Name: "example.com."}, // already in existing, shouldn't duplicate
+		{Type: "BOGUS", Name: "example.com."},
+	}
+
+	result := expectedRecordTypes(expected, existing)
+
+	assert.ElementsMatch(t, []uint16{dns.TypeSOA, dns.TypeNS, dns.TypeAAAA}, result)
+}
+
 func TestDeduplicate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -234,98 +262,25 @@ func TestParseDNSRecord(t *testing.T) {
 	}
 }
 
-func TestDetectChanges(t *testing.T) {
+func TestIsDNSSECOnlyType(t *testing.T) {
 	tests := []struct {
-		name       string
-		oldRecords []common.DNSRecord
-		newRecords []common.DNSRecord
-		expected   []string
+		name     string
+		rrtype   uint16
+		expected bool
 	}{
-		{
-			name: "No changes",
-			oldRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-			},
-			newRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-			},
-			expected: []string(nil),
-		},
-		{
-			name: "Added records",
-			oldRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-			},
-			newRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-			},
-			expected: []string{
-				"NEW: TXT example.com. -> v=spf1 -all",
-			},
-		},
-		{
-			name: "Deleted records",
-			oldRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-			},
-			newRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-			},
-			expected: []string{
-				"DELETED: TXT example.com. -> v=spf1 -all",
-			},
-		},
-		{
-			name: "Modified records",
-			oldRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-			},
-			newRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "20 mail2.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-			},
-			expected: []string{
-				"ADDED: MX example.com. -> 20 mail2.example.com.",
-				"REMOVED: MX example.com. -> 10 mail.example.com.",
-			},
-		},
-		{
-			name: "Mixed changes",
-			oldRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "10 mail.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-				{Type: "CNAME", Name: "www.example.com.", Value: "example.com."},
-			},
-			newRecords: []common.DNSRecord{
-				{Type: "MX", Name: "example.com.", Value: "20 mail2.example.com."},
-				{Type: "TXT", Name: "example.com.", Value: "v=spf1 include:_spf.google.com -all"},
-				{Type: "TXT", Name: "_dmarc.example.com.", Value: "v=DMARC1; p=reject;"},
-			},
-			expected: []string{
-				"ADDED: MX example.com. -> 20 mail2.example.com.",
-				"ADDED: TXT example.com. -> v=spf1 include:_spf.google.com -all",
-				"DELETED: CNAME www.example.com. -> example.com.",
-				"NEW: TXT _dmarc.example.com. -> v=DMARC1; p=reject;",
-				"REMOVED: MX example.com. -> 10 mail.example.com.",
-				"REMOVED: TXT example.com. -> v=spf1 -all",
-			},
-		},
+		{"RRSIG", dns.TypeRRSIG, true},
+		{"DNSKEY", dns.TypeDNSKEY, true},
+		{"DS", dns.TypeDS, true},
+		{"NSEC", dns.TypeNSEC, true},
+		{"NSEC3", dns.TypeNSEC3, true},
+		{"A", dns.TypeA, false},
+		{"TXT", dns.TypeTXT, false},
+		{"CNAME", dns.TypeCNAME, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := DetectChanges(tt.oldRecords, tt.newRecords)
-
-			// Sort both slices for consistent comparison
-			sort.Strings(result)
-			sort.Strings(tt.expected)
-
-			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expected, isDNSSECOnlyType(tt.rrtype))
 		})
 	}
 }
@@ -391,21 +346,3 @@ func TestFormatRecordKey(t *testing.T) {
 		})
 	}
 }
-
-func TestCreateValueMap(t *testing.T) {
-	records := []common.DNSRecord{
-		{Type: "MX", Name: "example.com.", Value: "10 mail1.example.com."},
-		{Type: "MX", Name: "example.com.", Value: "20 mail2.example.com."},
-		{Type: "TXT", Name: "example.com.", Value: "v=spf1 -all"},
-	}
-
-	expected := map[string]bool{
-		"10 mail1.example.com.": true,
-		"20 mail2.example.com.": true,
-		"v=spf1 -all":           true,
-	}
-
-	result := createValueMap(records)
-
-	assert.Equal(t, expected, result)
-}
@@ -0,0 +1,163 @@
+package dns
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DNSSEC event categories, surfaced through the same "CATEGORY: detail"
+// vocabulary as the diff and drift packages, so they can be logged and
+// notified alongside change-over-time and drift-from-baseline events.
+const (
+	DNSSECExpiringSignature = "DNSSEC_EXPIRING"    // an RRSIG nears its Expiration
+	DNSSECValidationFailure = "DNSSEC_VALIDATION"  // AD flag absent, or offline verification failed
+	DNSSECKeyRotation       = "DNSSEC_KEY_ROTATED" // the zone's DNSKEY key tags changed since the last check
+)
+
+// DNSSECEvent describes one DNSSEC-related condition surfaced during a
+// check.
+type DNSSECEvent struct {
+	Category string
+	Name     string
+	Detail   string
+}
+
+// String renders e as a single notification line.
+func (e DNSSECEvent) String() string {
+	return fmt.Sprintf("%s: %s -> %s", e.Category, e.Name, e.Detail)
+}
+
+// CheckDNSSEC inspects the raw responses FetchDNSRecords collected with the
+// DO bit set (see common.Config.DNSSECEnabled) and reports:
+//
+//   - DNSSECExpiringSignature for any RRSIG whose Expiration falls within
+//     expiryWindow of now (skipped when expiryWindow is zero);
+//   - DNSSECValidationFailure for any RRSIG from a response whose own AD
+//     flag isn't set, i.e. the resolver didn't validate it itself: that
+//     RRSIG is verified offline against the fetched DNSKEY set with
+//     dns.RRSIG.Verify;
+//   - DNSSECKeyRotation when the zone's current DNSKEY key tags differ from
+//     prevKeyTags, the baseline from the previous check.
+//
+// It also returns the current DNSKEY key tags, sorted, so the caller can
+// store them as the new baseline for the next check. If no DNSKEY was
+// observed this time (e.g. the query failed rather than the zone actually
+// having none), prevKeyTags is returned unchanged instead of being treated
+// as a rotation to an empty key set.
+func CheckDNSSEC(responses []*dns.Msg, prevKeyTags []uint16, expiryWindow time.Duration) ([]DNSSECEvent, []uint16) {
+	var events []DNSSECEvent
+
+	dnskeysByName := make(map[string][]*dns.DNSKEY)
+	keyTagSet := make(map[uint16]bool)
+	for _, r := range responses {
+		for _, rr := range r.Answer {
+			if key, ok := rr.(*dns.DNSKEY); ok {
+				dnskeysByName[key.Header().Name] = append(dnskeysByName[key.Header().Name], key)
+				keyTagSet[key.KeyTag()] = true
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, r := range responses {
+		for _, rr := range r.Answer {
+			sig, ok := rr.(*dns.RRSIG)
+			if !ok {
+				continue
+			}
+
+			if expiryWindow > 0 {
+				expiration := time.Unix(int64(sig.Expiration), 0)
+				if expiration.Sub(now) <= expiryWindow {
+					events = append(events, DNSSECEvent{
+						Category: DNSSECExpiringSignature,
+						Name:     sig.Header().Name,
+						Detail:   fmt.Sprintf("RRSIG(%s) expires %s", dns.TypeToString[sig.TypeCovered], expiration.Format(time.RFC1123)),
+					})
+				}
+			}
+
+			if r.AuthenticatedData {
+				// This response's own resolver already validated it.
+				continue
+			}
+			if !verifyRRSIGOffline(r, sig, dnskeysByName[sig.SignerName]) {
+				events = append(events, DNSSECEvent{
+					Category: DNSSECValidationFailure,
+					Name:     sig.Header().Name,
+					Detail:   fmt.Sprintf("RRSIG(%s) failed offline signature verification", dns.TypeToString[sig.TypeCovered]),
+				})
+			}
+		}
+	}
+
+	currentKeyTags := make([]uint16, 0, len(keyTagSet))
+	for tag := range keyTagSet {
+		currentKeyTags = append(currentKeyTags, tag)
+	}
+	sort.Slice(currentKeyTags, func(i, j int) bool { return currentKeyTags[i] < currentKeyTags[j] })
+
+	if len(currentKeyTags) == 0 {
+		// No DNSKEY was observed this check (most likely a failed or
+		// skipped query, not an actual key wipe): keep reporting the
+		// previous baseline rather than treating it as a rotation to an
+		// empty key set and losing it.
+		return events, prevKeyTags
+	}
+
+	if len(prevKeyTags) > 0 && !equalKeyTags(prevKeyTags, currentKeyTags) {
+		events = append(events, DNSSECEvent{
+			Category: DNSSECKeyRotation,
+			Name:     "DNSKEY",
+			Detail:   fmt.Sprintf("key tags %v -> %v", prevKeyTags, currentKeyTags),
+		})
+	}
+
+	return events, currentKeyTags
+}
+
+// verifyRRSIGOffline checks sig against the RRset it covers within the same
+// response r, trying each of keys in turn since a zone may keep more than
+// one DNSKEY (e.g. during a rollover).
+func verifyRRSIGOffline(r *dns.Msg, sig *dns.RRSIG, keys []*dns.DNSKEY) bool {
+	if len(keys) == 0 {
+		return false
+	}
+
+	var rrset []dns.RR
+	for _, rr := range r.Answer {
+		if rr.Header().Rrtype == sig.TypeCovered && rr.Header().Name == sig.Header().Name {
+			rrset = append(rrset, rr)
+		}
+	}
+	if len(rrset) == 0 {
+		return false
+	}
+
+	for _, key := range keys {
+		if key.KeyTag() != sig.KeyTag {
+			continue
+		}
+		if err := sig.Verify(key, rrset); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// equalKeyTags reports whether a and b, both already sorted, hold the same
+// key tags.
+func equalKeyTags(a, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package dns
+
+import (
+	"crypto"
+	"testing"
+	"time"
+
+	gdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSSECEvent_String(t *testing.T) {
+	event := DNSSECEvent{Category: DNSSECExpiringSignature, Name: "example.com.", Detail: "RRSIG(A) expires soon"}
+	assert.Equal(t, "DNSSEC_EXPIRING: example.com. -> RRSIG(A) expires soon", event.String())
+}
+
+func TestCheckDNSSEC_ExpiringSignature(t *testing.T) {
+	name := "example.com."
+	soon := uint32(time.Now().Add(time.Hour).Unix())
+
+	resp := new(gdns.Msg)
+	resp.AuthenticatedData = true // validating resolver, so offline verification is skipped
+	resp.Answer = []gdns.RR{
+		&gdns.RRSIG{
+			Hdr:         gdns.RR_Header{Name: name},
+			TypeCovered: gdns.TypeA,
+			SignerName:  name,
+			Expiration:  soon,
+		},
+	}
+
+	events, _ := CheckDNSSEC([]*gdns.Msg{resp}, nil, 24*time.Hour)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, DNSSECExpiringSignature, events[0].Category)
+	assert.Equal(t, name, events[0].Name)
+}
+
+func TestCheckDNSSEC_ValidatingResolverSkipsOfflineVerification(t *testing.T) {
+	name := "example.com."
+	farFuture := uint32(time.Now().Add(365 * 24 * time.Hour).Unix())
+
+	resp := new(gdns.Msg)
+	resp.AuthenticatedData = true
+	resp.Answer = []gdns.RR{
+		&gdns.RRSIG{Hdr: gdns.RR_Header{Name: name}, TypeCovered: gdns.TypeA, SignerName: name, Expiration: farFuture},
+	}
+
+	events, _ := CheckDNSSEC([]*gdns.Msg{resp}, nil, 0)
+
+	assert.Empty(t, events, "a validating resolver's AD flag should be trusted without offline verification")
+}
+
+func TestCheckDNSSEC_OfflineVerificationFailure(t *testing.T) {
+	name := "example.com."
+	farFuture := uint32(time.Now().Add(365 * 24 * time.Hour).Unix())
+
+	key := &gdns.DNSKEY{Hdr: gdns.RR_Header{Name: name}, Flags: 257, Protocol: 3, Algorithm: gdns.ECDSAP256SHA256}
+	_, err := key.Generate(256)
+	require.NoError(t, err)
+
+	resp := new(gdns.Msg)
+	resp.AuthenticatedData = false
+	resp.Answer = []gdns.RR{
+		key,
+		&gdns.A{Hdr: gdns.RR_Header{Name: name, Rrtype: gdns.TypeA}, A: []byte{192, 0, 2, 1}},
+		// KeyTag deliberately doesn't match any DNSKEY above, so verification fails.
+		&gdns.RRSIG{Hdr: gdns.RR_Header{Name: name}, TypeCovered: gdns.TypeA, SignerName: name, KeyTag: key.KeyTag() + 1, Expiration: farFuture},
+	}
+
+	events, currentKeyTags := CheckDNSSEC([]*gdns.Msg{resp}, nil, 0)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, DNSSECValidationFailure, events[0].Category)
+	assert.Equal(t, []uint16{key.KeyTag()}, currentKeyTags)
+}
+
+func TestCheckDNSSEC_OfflineVerificationSuccess(t *testing.T) {
+	name := "example.com."
+	farFuture := uint32(time.Now().Add(365 * 24 * time.Hour).Unix())
+
+	key := &gdns.DNSKEY{Hdr: gdns.RR_Header{Name: name, Rrtype: gdns.TypeDNSKEY, Ttl: 3600}, Flags: 257, Protocol: 3, Algorithm: gdns.ECDSAP256SHA256}
+	priv, err := key.Generate(256)
+	require.NoError(t, err)
+
+	a := &gdns.A{Hdr: gdns.RR_Header{Name: name, Rrtype: gdns.TypeA, Ttl: 3600}, A: []byte{192, 0, 2, 1}}
+	sig := &gdns.RRSIG{
+		Hdr:        gdns.RR_Header{Name: name},
+		KeyTag:     key.KeyTag(),
+		SignerName: name,
+		Algorithm:  key.Algorithm,
+		Expiration: farFuture,
+		Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+	}
+	require.NoError(t, sig.Sign(priv.(crypto.Signer), []gdns.RR{a}))
+
+	resp := new(gdns.Msg)
+	resp.AuthenticatedData = false
+	resp.Answer = []gdns.RR{key, a, sig}
+
+	events, currentKeyTags := CheckDNSSEC([]*gdns.Msg{resp}, nil, 0)
+
+	assert.Empty(t, events, "a correctly signed RRset should pass offline verification")
+	assert.Equal(t, []uint16{key.KeyTag()}, currentKeyTags)
+}
+
+func TestCheckDNSSEC_KeyRotationDetected(t *testing.T) {
+	resp := new(gdns.Msg)
+	resp.Answer = []gdns.RR{
+		&gdns.DNSKEY{Hdr: gdns.RR_Header{Name: "example.com."}, Flags: 257, Protocol: 3, Algorithm: gdns.ECDSAP256SHA256, PublicKey: "new-key"},
+	}
+
+	events, currentKeyTags := CheckDNSSEC([]*gdns.Msg{resp}, []uint16{1, 2, 3}, 0)
+
+	require.NotEmpty(t, currentKeyTags)
+	assert.NotEqual(t, []uint16{1, 2, 3}, currentKeyTags)
+
+	var categories []string
+	for _, e := range events {
+		categories = append(categories, e.Category)
+	}
+	assert.Contains(t, categories, DNSSECKeyRotation)
+}
+
+func TestCheckDNSSEC_NoRotationWhenKeyTagsUnchanged(t *testing.T) {
+	key := &gdns.DNSKEY{Hdr: gdns.RR_Header{Name: "example.com."}, Flags: 257, Protocol: 3, Algorithm: gdns.ECDSAP256SHA256, PublicKey: "same-key"}
+
+	resp := new(gdns.Msg)
+	resp.AuthenticatedData = true
+	resp.Answer = []gdns.RR{key}
+
+	events, currentKeyTags := CheckDNSSEC([]*gdns.Msg{resp}, []uint16{key.KeyTag()}, 0)
+
+	assert.Equal(t, []uint16{key.KeyTag()}, currentKeyTags)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,116 @@
+package dns
+
+import (
+	"dns-monitor/internal/common"
+	"fmt"
+	"sort"
+)
+
+// Drift categories. They mirror the diff package's CREATE/DELETE/CHANGE
+// vocabulary, but describe a comparison against a desired-state baseline
+// rather than against a previous snapshot.
+const (
+	DriftMissing    = "MISSING"    // expected but not observed
+	DriftUnexpected = "UNEXPECTED" // observed but not expected
+	DriftMismatch   = "MISMATCH"   // observed with a different value or TTL
+)
+
+// DriftEvent describes one discrepancy between an expected (desired-state)
+// DNS record and what's actually live.
+type DriftEvent struct {
+	Category string
+	Type     string
+	Name     string
+	Expected common.DNSRecord // zero value unless Category is MISSING or MISMATCH
+	Actual   common.DNSRecord // zero value unless Category is UNEXPECTED or MISMATCH
+}
+
+// String renders e in the same "CATEGORY: detail" vocabulary as the diff
+// package's Hunk/Entry rendering, so drift events can be logged and
+// notified alongside change-over-time ones.
+func (e DriftEvent) String() string {
+	switch e.Category {
+	case DriftMissing:
+		return fmt.Sprintf("MISSING: %s %s -> expected %s", e.Type, e.Name, e.Expected.Value)
+	case DriftUnexpected:
+		return fmt.Sprintf("UNEXPECTED: %s %s -> %s", e.Type, e.Name, e.Actual.Value)
+	case DriftMismatch:
+		return fmt.Sprintf("MISMATCH: %s %s -> expected %s (ttl %d), got %s (ttl %d)",
+			e.Type, e.Name, e.Expected.Value, e.Expected.TTL, e.Actual.Value, e.Actual.TTL)
+	default:
+		return fmt.Sprintf("%s: %s %s", e.Category, e.Type, e.Name)
+	}
+}
+
+// DetectDrift compares expected, a desired-state baseline, against current,
+// the live DNS records from this check, and reports every discrepancy:
+// MISSING records the baseline requires but that aren't live, UNEXPECTED
+// records that are live but aren't in the baseline, and MISMATCH records
+// that are live under an expected name with a different value or TTL.
+//
+// DetectDrift is independent of the diff package: a caller can run both
+// over the same current records to get change-over-time and
+// drift-from-baseline alerts from a single check.
+func DetectDrift(expected, current []common.DNSRecord) []DriftEvent {
+	expectedMap := buildRecordMap(expected)
+	currentMap := buildRecordMap(current)
+
+	var events []DriftEvent
+	for key, expectedRecs := range expectedMap {
+		currentRecs, exists := currentMap[key]
+		if !exists {
+			for _, rec := range expectedRecs {
+				events = append(events, DriftEvent{Category: DriftMissing, Type: rec.Type, Name: rec.Name, Expected: rec})
+			}
+			continue
+		}
+		events = append(events, detectGroupDrift(expectedRecs, currentRecs)...)
+	}
+
+	for key, currentRecs := range currentMap {
+		if _, exists := expectedMap[key]; !exists {
+			for _, rec := range currentRecs {
+				events = append(events, DriftEvent{Category: DriftUnexpected, Type: rec.Type, Name: rec.Name, Actual: rec})
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].String() < events[j].String()
+	})
+	return events
+}
+
+// detectGroupDrift compares expected and current records within the same
+// Type:Name group: a value present on both sides with a differing TTL is a
+// MISMATCH, a value only in expected is MISSING, and a value only in
+// current is UNEXPECTED.
+func detectGroupDrift(expectedRecs, currentRecs []common.DNSRecord) []DriftEvent {
+	currentByValue := make(map[string]common.DNSRecord, len(currentRecs))
+	for _, rec := range currentRecs {
+		currentByValue[rec.Value] = rec
+	}
+
+	matchedValues := make(map[string]bool, len(expectedRecs))
+
+	var events []DriftEvent
+	for _, exp := range expectedRecs {
+		cur, ok := currentByValue[exp.Value]
+		if !ok {
+			events = append(events, DriftEvent{Category: DriftMissing, Type: exp.Type, Name: exp.Name, Expected: exp})
+			continue
+		}
+		matchedValues[exp.Value] = true
+		if cur.TTL != exp.TTL {
+			events = append(events, DriftEvent{Category: DriftMismatch, Type: exp.Type, Name: exp.Name, Expected: exp, Actual: cur})
+		}
+	}
+
+	for _, cur := range currentRecs {
+		if !matchedValues[cur.Value] {
+			events = append(events, DriftEvent{Category: DriftUnexpected, Type: cur.Type, Name: cur.Name, Actual: cur})
+		}
+	}
+
+	return events
+}
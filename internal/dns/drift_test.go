@@ -0,0 +1,54 @@
+package dns
+
+import (
+	"dns-monitor/internal/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectDrift(t *testing.T) {
+	expected := []common.DNSRecord{
+		{Type: "A", Name: "example.com", Value: "1.2.3.4", TTL: 300},
+		{Type: "MX", Name: "example.com", Value: "10 mail.example.com"},
+		{Type: "TXT", Name: "_dmarc.example.com", Value: "v=DMARC1; p=reject"},
+	}
+
+	current := []common.DNSRecord{
+		{Type: "A", Name: "example.com", Value: "1.2.3.4", TTL: 60},            // TTL mismatch
+		{Type: "TXT", Name: "_dmarc.example.com", Value: "v=DMARC1; p=reject"}, // matches
+		{Type: "CNAME", Name: "www.example.com", Value: "example.com"},         // unexpected
+	}
+
+	events := DetectDrift(expected, current)
+
+	var categories []string
+	for _, e := range events {
+		categories = append(categories, e.Category+":"+e.Type+":"+e.Name)
+	}
+
+	assert.Contains(t, categories, "MISMATCH:A:example.com")
+	assert.Contains(t, categories, "MISSING:MX:example.com")
+	assert.Contains(t, categories, "UNEXPECTED:CNAME:www.example.com")
+	assert.Len(t, events, 3)
+}
+
+func TestDetectDrift_NoDiscrepancies(t *testing.T) {
+	records := []common.DNSRecord{
+		{Type: "A", Name: "example.com", Value: "1.2.3.4", TTL: 300},
+	}
+
+	assert.Empty(t, DetectDrift(records, records))
+}
+
+func TestDriftEvent_String(t *testing.T) {
+	event := DriftEvent{
+		Category: DriftMismatch,
+		Type:     "A",
+		Name:     "example.com",
+		Expected: common.DNSRecord{Value: "1.2.3.4", TTL: 300},
+		Actual:   common.DNSRecord{Value: "1.2.3.4", TTL: 60},
+	}
+
+	assert.Equal(t, "MISMATCH: A example.com -> expected 1.2.3.4 (ttl 300), got 1.2.3.4 (ttl 60)", event.String())
+}
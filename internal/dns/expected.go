@@ -0,0 +1,52 @@
+package dns
+
+import (
+	"bytes"
+	"dns-monitor/internal/common"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/miekg/dns"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadExpectedState reads the desired-state baseline used by DetectDrift
+// from path. A ".yaml" or ".yml" file is unmarshaled directly as a list of
+// DNSRecord entries; anything else is parsed as an RFC 1035 zone file with
+// miekg/dns's ZoneParser.
+func LoadExpectedState(path string) ([]common.DNSRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected state file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var records []common.DNSRecord
+		if err := yaml.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse expected state YAML: %w", err)
+		}
+		return records, nil
+	default:
+		return parseZoneFile(data)
+	}
+}
+
+// parseZoneFile parses data as an RFC 1035 zone file, converting each
+// resource record into a DNSRecord with the same field mapping used for
+// live query responses.
+func parseZoneFile(data []byte) ([]common.DNSRecord, error) {
+	zp := dns.NewZoneParser(bytes.NewReader(data), ".", "")
+
+	var records []common.DNSRecord
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		records = append(records, parseDNSRecord(rr, rr.Header().Rrtype))
+	}
+	if err := zp.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse expected state zone file: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,57 @@
+package dns
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadExpectedState_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expected.yaml")
+	content := `
+- type: A
+  name: example.com.
+  value: 1.2.3.4
+  ttl: 300
+- type: MX
+  name: example.com.
+  value: "10 mail.example.com"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	records, err := LoadExpectedState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, "A", records[0].Type)
+	assert.Equal(t, "1.2.3.4", records[0].Value)
+	assert.Equal(t, uint32(300), records[0].TTL)
+}
+
+func TestLoadExpectedState_ZoneFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expected.zone")
+	content := "example.com. 300 IN A 1.2.3.4\nexample.com. 300 IN MX 10 mail.example.com.\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	records, err := LoadExpectedState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, "A", records[0].Type)
+	assert.Equal(t, "1.2.3.4", records[0].Value)
+}
+
+func TestLoadExpectedState_MissingFile(t *testing.T) {
+	_, err := LoadExpectedState(filepath.Join(t.TempDir(), "missing.zone"))
+	assert.Error(t, err)
+}
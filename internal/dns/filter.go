@@ -0,0 +1,65 @@
+package dns
+
+import (
+	"dns-monitor/internal/common"
+	"log"
+	"path"
+	"strings"
+)
+
+// FilterRecords drops any record in records matching config.IgnoredNames (a
+// path.Match glob against Name), config.IgnoredTargets (a path.Match glob
+// against Value), or config.IgnoredTypes (an exact match against Type),
+// borrowing the "ignored_names / ignored_targets" concept from dnscontrol so
+// operators can monitor zones with machine-generated churn (ACME challenges,
+// dynamic DNS, ephemeral CNAMEs) without getting paged on every rotation.
+//
+// When config.DryRunFilter is set, FilterRecords doesn't drop anything: it
+// logs which records would have been ignored and returns records unchanged,
+// so operators can validate their patterns before committing to them.
+func FilterRecords(records []common.DNSRecord, config common.Config) []common.DNSRecord {
+	if len(config.IgnoredNames) == 0 && len(config.IgnoredTargets) == 0 && len(config.IgnoredTypes) == 0 {
+		return records
+	}
+
+	filtered := make([]common.DNSRecord, 0, len(records))
+	for _, record := range records {
+		if reason, ignored := ignoreReason(record, config); ignored {
+			if config.DryRunFilter {
+				log.Printf("🔍 dry-run-filter: would ignore %s %s -> %s (%s)", record.Type, record.Name, record.Value, reason)
+				filtered = append(filtered, record)
+			}
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	return filtered
+}
+
+// ignoreReason reports whether record matches any of config's ignore lists,
+// and a short description of which one for logging.
+func ignoreReason(record common.DNSRecord, config common.Config) (string, bool) {
+	for _, ignoredType := range config.IgnoredTypes {
+		if record.Type == ignoredType {
+			return "type matches " + ignoredType, true
+		}
+	}
+	// DNS records come back as FQDNs with a trailing dot (see dns.go's
+	// parseDNSRecord), but operators write patterns the way they'd type a
+	// hostname, e.g. "*.dyn.example.com". Trim the trailing dot from both
+	// sides so a pattern like that matches without the caller having to
+	// know about the wire-format quirk.
+	name := strings.TrimSuffix(record.Name, ".")
+	value := strings.TrimSuffix(record.Value, ".")
+	for _, pattern := range config.IgnoredNames {
+		if matched, _ := path.Match(pattern, name); matched {
+			return "name matches " + pattern, true
+		}
+	}
+	for _, pattern := range config.IgnoredTargets {
+		if matched, _ := path.Match(pattern, value); matched {
+			return "target matches " + pattern, true
+		}
+	}
+	return "", false
+}
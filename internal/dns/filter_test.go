@@ -0,0 +1,78 @@
+package dns
+
+import (
+	"dns-monitor/internal/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterRecords_NoIgnoreListsIsNoop(t *testing.T) {
+	records := []common.DNSRecord{{Type: "A", Name: "example.com", Value: "1.2.3.4"}}
+
+	filtered := FilterRecords(records, common.Config{})
+
+	assert.Equal(t, records, filtered)
+}
+
+func TestFilterRecords_IgnoredNames(t *testing.T) {
+	records := []common.DNSRecord{
+		{Type: "A", Name: "host1.dyn.example.com", Value: "1.2.3.4"},
+		{Type: "A", Name: "example.com", Value: "1.2.3.5"},
+	}
+
+	filtered := FilterRecords(records, common.Config{IgnoredNames: []string{"*.dyn.example.com"}})
+
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "example.com", filtered[0].Name)
+}
+
+func TestFilterRecords_IgnoredNamesMatchesTrailingDotFQDN(t *testing.T) {
+	records := []common.DNSRecord{
+		{Type: "A", Name: "host1.dyn.example.com.", Value: "1.2.3.4"},
+		{Type: "A", Name: "example.com.", Value: "1.2.3.5"},
+	}
+
+	filtered := FilterRecords(records, common.Config{IgnoredNames: []string{"*.dyn.example.com"}})
+
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "example.com.", filtered[0].Name)
+}
+
+func TestFilterRecords_IgnoredTargets(t *testing.T) {
+	records := []common.DNSRecord{
+		{Type: "CNAME", Name: "assets.example.com", Value: "d123.cloudfront.net"},
+		{Type: "CNAME", Name: "www.example.com", Value: "example.com"},
+	}
+
+	filtered := FilterRecords(records, common.Config{IgnoredTargets: []string{"*.cloudfront.net"}})
+
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "www.example.com", filtered[0].Name)
+}
+
+func TestFilterRecords_IgnoredTypes(t *testing.T) {
+	records := []common.DNSRecord{
+		{Type: "TXT", Name: "example.com", Value: "v=spf1 ~all"},
+		{Type: "A", Name: "example.com", Value: "1.2.3.4"},
+	}
+
+	filtered := FilterRecords(records, common.Config{IgnoredTypes: []string{"TXT"}})
+
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "A", filtered[0].Type)
+}
+
+func TestFilterRecords_DryRunKeepsEverything(t *testing.T) {
+	records := []common.DNSRecord{
+		{Type: "A", Name: "host1.dyn.example.com", Value: "1.2.3.4"},
+		{Type: "A", Name: "example.com", Value: "1.2.3.5"},
+	}
+
+	filtered := FilterRecords(records, common.Config{
+		IgnoredNames: []string{"*.dyn.example.com"},
+		DryRunFilter: true,
+	})
+
+	assert.Equal(t, records, filtered)
+}
@@ -0,0 +1,195 @@
+package dns
+
+import (
+	"context"
+	"dns-monitor/internal/common"
+	"dns-monitor/internal/dns/diff"
+	"dns-monitor/internal/metrics"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Propagation event categories, in the same "CATEGORY: detail" vocabulary as
+// the diff and drift packages.
+const (
+	PropagationConfirmed = "PROPAGATED"          // every resolver agrees with the new state
+	PropagationPartial   = "PROPAGATION_PARTIAL" // resolvers still disagree after the timeout
+)
+
+// PropagationEvent describes the propagation outcome for one changed RRset.
+type PropagationEvent struct {
+	Category string
+	Type     string
+	Name     string
+	Detail   string
+}
+
+// String renders e as a single notification line.
+func (e PropagationEvent) String() string {
+	return fmt.Sprintf("%s: %s %s -> %s", e.Category, e.Type, e.Name, e.Detail)
+}
+
+// CheckPropagation re-queries each changed RRset in hunks against every
+// resolver in config.PropagationResolvers, polling every
+// config.PropagationInterval, until either all of them agree with the new
+// value or config.PropagationTimeout elapses. It's modeled on how ACME
+// DNS-01 clients wait for propagation before asking a CA to validate: a
+// single lagging resolver shouldn't turn a real change into a premature
+// "inconsistent" alert.
+//
+// CheckPropagation is a no-op (returns nil immediately) when
+// config.PropagationResolvers is empty, so callers who want the raw diff
+// notified immediately can simply leave it unset. UNCHANGED hunks are
+// skipped; only CREATE, DELETE, and CHANGE are checked.
+//
+// currentRecords is the record set the change was diffed against (i.e. what
+// FetchDNSRecords just returned), used to look up each hunk's post-change
+// values: a Hunk's own Entries carry the new value for a plain CREATE/CHANGE,
+// but leave it unset for a semantically-diffed TXT record (SPF/DMARC/DKIM),
+// where Entries.Note describes the change instead. Reading the wanted value
+// back out of currentRecords works uniformly for both.
+//
+// CheckPropagation blocks the calling goroutine for up to
+// config.PropagationTimeout; ctx cancellation aborts the wait early and the
+// hunks still pending agreement are reported as PropagationPartial.
+func CheckPropagation(ctx context.Context, config common.Config, hunks []diff.Hunk, currentRecords []common.DNSRecord, mtr *metrics.Metrics) []PropagationEvent {
+	if len(config.PropagationResolvers) == 0 {
+		return nil
+	}
+
+	var events []PropagationEvent
+	for _, hunk := range hunks {
+		if hunk.Verb == diff.Unchanged {
+			continue
+		}
+		events = append(events, checkHunkPropagation(ctx, config, hunk, currentRecords, mtr))
+	}
+	return events
+}
+
+// checkHunkPropagation polls config.PropagationResolvers for one changed
+// RRset until they all agree with hunk's new values or the timeout expires.
+func checkHunkPropagation(ctx context.Context, config common.Config, hunk diff.Hunk, currentRecords []common.DNSRecord, mtr *metrics.Metrics) PropagationEvent {
+	wanted := wantedValues(hunk, currentRecords)
+	recordType := dns.StringToType[hunk.Type]
+
+	deadline := time.Now().Add(config.PropagationTimeout)
+	disagreement := pollResolvers(ctx, config, recordType, hunk.Name, wanted, mtr)
+	for len(disagreement) > 0 && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return PropagationEvent{Category: PropagationPartial, Type: hunk.Type, Name: hunk.Name, Detail: describeDisagreement(disagreement)}
+		case <-time.After(config.PropagationInterval):
+		}
+		disagreement = pollResolvers(ctx, config, recordType, hunk.Name, wanted, mtr)
+	}
+
+	if len(disagreement) == 0 {
+		return PropagationEvent{
+			Category: PropagationConfirmed,
+			Type:     hunk.Type,
+			Name:     hunk.Name,
+			Detail:   fmt.Sprintf("confirmed across %d resolver(s)", len(config.PropagationResolvers)),
+		}
+	}
+	return PropagationEvent{Category: PropagationPartial, Type: hunk.Type, Name: hunk.Name, Detail: describeDisagreement(disagreement)}
+}
+
+// wantedValues collects the post-change values expected for hunk's RRset,
+// read back out of currentRecords rather than hunk.Entries since a
+// semantically-diffed TXT hunk (see diff.diffGroup) carries its change as a
+// human-readable Note rather than an Old/New record pair. A DELETE hunk
+// naturally yields no matches, since currentRecords no longer has an entry
+// for the RRset, so every resolver is expected to return nothing.
+func wantedValues(hunk diff.Hunk, currentRecords []common.DNSRecord) map[string]bool {
+	wanted := make(map[string]bool)
+	for _, rec := range currentRecords {
+		if rec.Type == hunk.Type && rec.Name == hunk.Name {
+			wanted[rec.Value] = true
+		}
+	}
+	return wanted
+}
+
+// pollResolvers queries every resolver in config.PropagationResolvers
+// concurrently for name/recordType and returns the values seen from any
+// resolver that doesn't match wanted, keyed by resolver address. Querying
+// concurrently keeps one slow or unreachable resolver (which retries with
+// backoff inside queryDNS) from serializing in front of the rest.
+func pollResolvers(ctx context.Context, config common.Config, recordType uint16, name string, wanted map[string]bool, mtr *metrics.Metrics) map[string][]string {
+	disagreement := make(map[string][]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, resolver := range config.PropagationResolvers {
+		wg.Add(1)
+		go func(resolver string) {
+			defer wg.Done()
+
+			resolverConfig := config
+			resolverConfig.DNSServer = resolver
+
+			r, err := queryDNS(ctx, name, recordType, resolverConfig, mtr)
+			if err != nil {
+				mu.Lock()
+				disagreement[resolver] = []string{fmt.Sprintf("query failed: %v", err)}
+				mu.Unlock()
+				return
+			}
+
+			got := make(map[string]bool, len(r.Answer))
+			values := make([]string, 0, len(r.Answer))
+			for _, ans := range r.Answer {
+				value := parseDNSRecord(ans, ans.Header().Rrtype).Value
+				got[value] = true
+				values = append(values, value)
+			}
+
+			if !sameValues(got, wanted) {
+				mu.Lock()
+				disagreement[resolver] = values
+				mu.Unlock()
+			}
+		}(resolver)
+	}
+	wg.Wait()
+	return disagreement
+}
+
+// sameValues reports whether a and b contain exactly the same set of values.
+func sameValues(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for value := range a {
+		if !b[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// describeDisagreement renders the resolvers still disagreeing, sorted by
+// address, as a single notification-friendly detail string.
+func describeDisagreement(disagreement map[string][]string) string {
+	resolvers := make([]string, 0, len(disagreement))
+	for resolver := range disagreement {
+		resolvers = append(resolvers, resolver)
+	}
+	sort.Strings(resolvers)
+
+	parts := make([]string, 0, len(resolvers))
+	for _, resolver := range resolvers {
+		values := disagreement[resolver]
+		if len(values) == 0 {
+			parts = append(parts, fmt.Sprintf("%s: <empty>", resolver))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", resolver, strings.Join(values, ", ")))
+	}
+	return fmt.Sprintf("still disagreeing after timeout: %s", strings.Join(parts, "; "))
+}
@@ -0,0 +1,121 @@
+package dns
+
+import (
+	"context"
+	"dns-monitor/internal/common"
+	"dns-monitor/internal/dns/diff"
+	"net"
+	"testing"
+	"time"
+
+	gdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver starts a local UDP DNS server that always answers an A query
+// with value, and returns its address for use as a PropagationResolvers
+// entry. The caller must call the returned shutdown func.
+func fakeResolver(t *testing.T, value string) (addr string, shutdown func()) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	mux := gdns.NewServeMux()
+	mux.HandleFunc(".", func(w gdns.ResponseWriter, r *gdns.Msg) {
+		resp := new(gdns.Msg)
+		resp.SetReply(r)
+		if value != "" {
+			rr, err := gdns.NewRR(r.Question[0].Name + " 300 IN A " + value)
+			require.NoError(t, err)
+			resp.Answer = append(resp.Answer, rr)
+		}
+		w.WriteMsg(resp)
+	})
+
+	server := &gdns.Server{PacketConn: conn, Handler: mux}
+	go server.ActivateAndServe()
+
+	return conn.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+func TestCheckPropagation_Confirmed(t *testing.T) {
+	addr1, shutdown1 := fakeResolver(t, "192.0.2.1")
+	defer shutdown1()
+	addr2, shutdown2 := fakeResolver(t, "192.0.2.1")
+	defer shutdown2()
+
+	config := common.Config{
+		DNSClient:            gdns.Client{Timeout: 2 * time.Second},
+		PropagationResolvers: []string{addr1, addr2},
+		PropagationTimeout:   time.Second,
+		PropagationInterval:  10 * time.Millisecond,
+	}
+	hunks := []diff.Hunk{{
+		Verb:    diff.Change,
+		Type:    "A",
+		Name:    "example.com.",
+		Entries: []diff.Entry{{Verb: diff.Change, New: common.DNSRecord{Type: "A", Name: "example.com.", Value: "192.0.2.1"}}},
+	}}
+	currentRecords := []common.DNSRecord{{Type: "A", Name: "example.com.", Value: "192.0.2.1"}}
+
+	events := CheckPropagation(context.Background(), config, hunks, currentRecords, nil)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, PropagationConfirmed, events[0].Category)
+}
+
+func TestCheckPropagation_Partial(t *testing.T) {
+	addr1, shutdown1 := fakeResolver(t, "192.0.2.1")
+	defer shutdown1()
+	addr2, shutdown2 := fakeResolver(t, "192.0.2.99") // lagging resolver, never agrees
+	defer shutdown2()
+
+	config := common.Config{
+		DNSClient:            gdns.Client{Timeout: 2 * time.Second},
+		PropagationResolvers: []string{addr1, addr2},
+		PropagationTimeout:   50 * time.Millisecond,
+		PropagationInterval:  10 * time.Millisecond,
+	}
+	hunks := []diff.Hunk{{
+		Verb:    diff.Change,
+		Type:    "A",
+		Name:    "example.com.",
+		Entries: []diff.Entry{{Verb: diff.Change, New: common.DNSRecord{Type: "A", Name: "example.com.", Value: "192.0.2.1"}}},
+	}}
+	currentRecords := []common.DNSRecord{{Type: "A", Name: "example.com.", Value: "192.0.2.1"}}
+
+	events := CheckPropagation(context.Background(), config, hunks, currentRecords, nil)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, PropagationPartial, events[0].Category)
+	assert.Contains(t, events[0].Detail, addr2)
+}
+
+func TestCheckPropagation_Disabled(t *testing.T) {
+	hunks := []diff.Hunk{{Verb: diff.Change, Type: "A", Name: "example.com."}}
+
+	events := CheckPropagation(context.Background(), common.Config{}, hunks, nil, nil)
+
+	assert.Nil(t, events)
+}
+
+func TestWantedValues_SemanticTXTHunk(t *testing.T) {
+	// A semantically-diffed TXT hunk (SPF/DMARC/DKIM) carries its change as
+	// a Note, with no Old/New record on the entry; wantedValues must still
+	// resolve the real new value from currentRecords.
+	hunk := diff.Hunk{
+		Type:    "TXT",
+		Name:    "example.com.",
+		Entries: []diff.Entry{{Verb: diff.Change, Note: "SPF mechanism added: include:_spf.new.example.com"}},
+	}
+	currentRecords := []common.DNSRecord{
+		{Type: "TXT", Name: "example.com.", Value: "v=spf1 include:_spf.new.example.com ~all"},
+		{Type: "A", Name: "example.com.", Value: "192.0.2.1"},
+	}
+
+	wanted := wantedValues(hunk, currentRecords)
+
+	assert.Equal(t, map[string]bool{"v=spf1 include:_spf.new.example.com ~all": true}, wanted)
+}
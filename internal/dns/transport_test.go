@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"dns-monitor/internal/common"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gdns "github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeAResponse(req *gdns.Msg) *gdns.Msg {
+	resp := new(gdns.Msg)
+	resp.SetReply(req)
+	if len(req.Question) > 0 {
+		rr, _ := gdns.NewRR(req.Question[0].Name + " 3600 IN A 192.0.2.1")
+		resp.Answer = append(resp.Answer, rr)
+	}
+	return resp
+}
+
+func TestQueryDNS_DoH(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/dns-message" {
+			t.Errorf("expected Content-Type application/dns-message, got %q", r.Header.Get("Content-Type"))
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req := new(gdns.Msg)
+		require.NoError(t, req.Unpack(body))
+
+		packed, err := fakeAResponse(req).Pack()
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(packed)
+	}))
+	defer server.Close()
+
+	config := common.Config{
+		DNSServer:   server.URL,
+		DNSProtocol: common.DNSProtocolHTTPS,
+		HTTPClient:  server.Client(),
+	}
+
+	resp, err := queryDNS(context.Background(), "example.com", gdns.TypeA, config, nil)
+	require.NoError(t, err)
+	assert.Len(t, resp.Answer, 1)
+}
+
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestQueryDNS_DoT(t *testing.T) {
+	cert := selfSignedCert(t)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+
+	mux := gdns.NewServeMux()
+	mux.HandleFunc(".", func(w gdns.ResponseWriter, r *gdns.Msg) {
+		w.WriteMsg(fakeAResponse(r))
+	})
+
+	server := &gdns.Server{Listener: listener, Net: "tcp-tls", Handler: mux}
+	go server.ActivateAndServe()
+	defer server.Shutdown()
+
+	config := common.Config{
+		DNSServer:             listener.Addr().String(),
+		DNSProtocol:           common.DNSProtocolTLS,
+		DNSInsecureSkipVerify: true,
+		DNSClient:             gdns.Client{Timeout: 2 * time.Second},
+	}
+
+	resp, err := queryDNS(context.Background(), "example.com", gdns.TypeA, config, nil)
+	require.NoError(t, err)
+	assert.Len(t, resp.Answer, 1)
+}
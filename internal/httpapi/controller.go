@@ -0,0 +1,377 @@
+package httpapi
+
+import (
+	"context"
+	"dns-monitor/internal/common"
+	"dns-monitor/internal/dns"
+	"dns-monitor/internal/dns/diff"
+	"dns-monitor/internal/metrics"
+	"dns-monitor/internal/notification/providers"
+	"dns-monitor/internal/storage"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Controller owns the DNS-monitoring state (previous snapshot, last check
+// result) behind a mutex so both the ticker loop and the HTTP API can safely
+// trigger and observe checks.
+type Controller struct {
+	mu sync.Mutex
+
+	config   common.Config
+	notifier providers.Notifier
+	metrics  *metrics.Metrics
+	expected []common.DNSRecord
+
+	prevState common.PreviousState
+	lastCheck time.Time
+	nextCheck time.Time
+	lastErr   error
+
+	// checking guards against a second PerformCheck starting while one is
+	// mid-propagation-wait and has released mu (see PerformCheck). It's
+	// separate from mu, rather than just held across the whole call,
+	// precisely because mu still needs to be released for that wait.
+	checking atomic.Bool
+}
+
+// NewController creates a Controller seeded with the given previous state.
+func NewController(config common.Config, notifier providers.Notifier, prevState common.PreviousState) *Controller {
+	return &Controller{
+		config:    config,
+		notifier:  notifier,
+		prevState: prevState,
+		nextCheck: time.Now().Add(config.CheckInterval),
+	}
+}
+
+// SetMetrics attaches m so subsequent checks and notification sends are
+// recorded. Call it once, before the controller starts running; a nil or
+// never-set metrics (the default) simply disables metrics collection.
+func (c *Controller) SetMetrics(m *metrics.Metrics) {
+	c.metrics = m
+}
+
+// SetExpectedState attaches a desired-state baseline, so PerformCheck also
+// runs DetectDrift against it and scopes its DNS queries to the names it
+// lists. Call it once, before the controller starts running; a nil or
+// never-set baseline (the default) disables desired-state mode entirely.
+func (c *Controller) SetExpectedState(expected []common.DNSRecord) {
+	c.expected = expected
+}
+
+// Status is a point-in-time snapshot of the controller's state.
+type Status struct {
+	Domain      string    `json:"domain"`
+	LastCheck   time.Time `json:"last_check"`
+	NextCheck   time.Time `json:"next_check"`
+	RecordCount int       `json:"record_count"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Status returns a snapshot of the controller's current state.
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := Status{
+		Domain:      c.config.Domain,
+		LastCheck:   c.lastCheck,
+		NextCheck:   c.nextCheck,
+		RecordCount: len(c.prevState.Records),
+	}
+	if c.lastErr != nil {
+		status.LastError = c.lastErr.Error()
+	}
+	return status
+}
+
+// Records returns the most recently stored DNS record snapshot.
+func (c *Controller) Records() []common.DNSRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	records := make([]common.DNSRecord, len(c.prevState.Records))
+	copy(records, c.prevState.Records)
+	return records
+}
+
+// PerformCheck fetches the current DNS records, diffs them against the
+// previous snapshot, and sends a notification and persists the new state on
+// any change. It is safe to call concurrently from the ticker loop and the
+// HTTP API: only one check runs at a time, enforced by checking rather than
+// mu alone, since mu is released for the propagation wait (see
+// dns.CheckPropagation) so Status()/Records() callers aren't also stalled
+// for its (potentially many minutes long) duration. A second call made
+// while one is already in flight returns an error immediately instead of
+// blocking, so it can't read or clobber prevState out of turn.
+func (c *Controller) PerformCheck(ctx context.Context) (diff.Result, error) {
+	if !c.checking.CompareAndSwap(false, true) {
+		return diff.Result{}, errors.New("a check is already in progress")
+	}
+	defer c.checking.Store(false)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	log.Printf("⏳ Checking DNS records for %s ... ⏳", c.config.Domain)
+	currentRecords, responses, err := dns.FetchDNSRecords(ctx, c.config, c.config.PrimaryDomainConfig(), c.metrics, c.expected)
+	c.lastCheck = time.Now()
+	c.nextCheck = c.lastCheck.Add(c.config.CheckInterval)
+	if c.metrics != nil {
+		c.metrics.RecordCheck(c.config.Domain, err)
+	}
+	if err != nil {
+		c.lastErr = err
+		log.Printf("Error fetching DNS records: %v", err)
+		if c.config.NotifyOnErrors {
+			c.sendErrorNotification(ctx, "Failed to fetch DNS records", err, providers.FailureTypeFetchError)
+		}
+		return diff.Result{}, err
+	}
+
+	currentRecords = dns.FilterRecords(currentRecords, c.config)
+
+	result := diff.Diff(c.prevState.Records, currentRecords)
+	if c.metrics != nil {
+		c.metrics.RecordChanges(c.config.Domain, result)
+	}
+
+	dnskeyTags := c.prevState.DNSKeyTags
+	if c.config.DNSSECEnabled {
+		var dnssecEvents []dns.DNSSECEvent
+		dnssecEvents, dnskeyTags = dns.CheckDNSSEC(responses, c.prevState.DNSKeyTags, c.config.DNSSECExpiryWindow)
+		if len(dnssecEvents) > 0 {
+			c.sendDNSSECNotification(ctx, dnssecEvents)
+		}
+	}
+
+	if result.Changed() {
+		var propagation []dns.PropagationEvent
+		if len(c.config.PropagationResolvers) > 0 {
+			// CheckPropagation can block for up to PropagationTimeout, far
+			// longer than the rest of a check; release c.mu around it so it
+			// doesn't also stall Status()/Records() callers and the next
+			// scheduled check for that long.
+			c.mu.Unlock()
+			propagation = dns.CheckPropagation(ctx, c.config, result.Changes(), currentRecords, c.metrics)
+			c.mu.Lock()
+		}
+		c.sendChangeDetectedNotification(ctx, result.Changes(), propagation)
+
+		newState := common.PreviousState{Records: currentRecords, DNSKeyTags: dnskeyTags}
+		if err := storage.SavePreviousState(newState, c.config.Domain); err != nil {
+			log.Printf("Failed to save updated state: %v", err)
+			c.lastErr = err
+			if c.config.NotifyOnErrors {
+				c.sendErrorNotification(ctx, "Failed to save updated state", err, providers.FailureTypeStateError)
+			}
+		}
+	} else {
+		log.Println("✅ No DNS changes detected ✅")
+	}
+
+	if len(c.expected) > 0 {
+		// Filter the baseline the same way as currentRecords: an expected
+		// record matching an ignore pattern shouldn't flip to MISSING just
+		// because it was dropped from one side of the comparison.
+		expected := dns.FilterRecords(c.expected, c.config)
+		if drift := dns.DetectDrift(expected, currentRecords); len(drift) > 0 {
+			c.sendDriftNotification(ctx, drift)
+		}
+	}
+
+	c.prevState = common.PreviousState{Records: currentRecords, DNSKeyTags: dnskeyTags}
+	c.lastErr = nil
+	return result, nil
+}
+
+// SendTestNotification sends a canned test alert through the configured
+// notifier, so operators can verify notifier wiring without waiting for a
+// real DNS change.
+func (c *Controller) SendTestNotification(ctx context.Context) error {
+	message := fmt.Sprintf("This is a test alert for %s, sent at %s", c.config.Domain, time.Now().Format(time.RFC1123))
+	title, message := c.formatNotification("DNS Monitor Test Notification", message)
+	return c.send(ctx, title, message, providers.NotificationContext{FailureType: providers.FailureTypeTest})
+}
+
+// SendStartupNotification announces a successful boot, so operators running
+// many dns-monitor instances can confirm a new deployment came up cleanly.
+func (c *Controller) SendStartupNotification(ctx context.Context) error {
+	message := fmt.Sprintf("🚀 DNS Monitor started for %s, checking every %s 🚀", c.config.Domain, c.config.CheckInterval)
+	title, message := c.formatNotification("DNS Monitor Started", message)
+	return c.send(ctx, title, message, providers.NotificationContext{})
+}
+
+// RunHeartbeat sends a periodic "still alive" notification every
+// HeartbeatInterval, until ctx is cancelled. It is a no-op if
+// HeartbeatInterval is unset. Callers should run it in its own goroutine.
+func (c *Controller) RunHeartbeat(ctx context.Context) {
+	interval := c.config.NotificationConfig.HeartbeatInterval
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.sendHeartbeat(ctx)
+		}
+	}
+}
+
+func (c *Controller) sendHeartbeat(ctx context.Context) {
+	status := c.Status()
+	message := fmt.Sprintf("💓 %s still alive, last check OK at %s 💓", status.Domain, status.LastCheck.Format(time.RFC1123))
+	title, message := c.formatNotification("DNS Monitor Heartbeat", message)
+	if err := c.send(ctx, title, message, providers.NotificationContext{}); err != nil {
+		log.Printf("Failed to send heartbeat notification: %v", err)
+	}
+}
+
+// sendChangeDetectedNotification alerts on a raw diff against the previous
+// snapshot. propagation, when non-nil (i.e. the propagation checker is
+// enabled via PropagationResolvers), is appended as a section reporting
+// whether each changed RRset has since been confirmed live everywhere or is
+// still propagating; see dns.CheckPropagation.
+func (c *Controller) sendChangeDetectedNotification(ctx context.Context, hunks []diff.Hunk, propagation []dns.PropagationEvent) {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("⚠️ DNS CHANGES DETECTED for %s ⚠️\n\n", c.config.Domain))
+	for i, hunk := range hunks {
+		builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, hunk))
+	}
+	if len(propagation) > 0 {
+		builder.WriteString("\nPropagation:\n")
+		for i, event := range propagation {
+			builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, event))
+		}
+	}
+	builder.WriteString(fmt.Sprintf("\nDetected at: %s", time.Now().Format(time.RFC1123)))
+
+	message := builder.String()
+	log.Println(message)
+	title, message := c.formatNotification("DNS Change Alert", message)
+
+	changes := make([]string, len(hunks))
+	for i, hunk := range hunks {
+		changes[i] = hunk.String()
+	}
+
+	nctx := providers.NotificationContext{FailureType: providers.FailureTypeDNSChange, Changes: changes}
+	if err := c.send(ctx, title, message, nctx); err != nil {
+		log.Printf("❌ Error sending notification: %v ❌", err)
+	} else {
+		log.Println("✅ Notification sent successfully ✅")
+	}
+}
+
+// sendDriftNotification alerts on a desired-state baseline diverging from
+// the live records, composably with sendChangeDetectedNotification: both
+// can fire from the same PerformCheck run.
+func (c *Controller) sendDriftNotification(ctx context.Context, drift []dns.DriftEvent) {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("📐 DNS DRIFT DETECTED for %s 📐\n\n", c.config.Domain))
+	for i, event := range drift {
+		builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, event))
+	}
+	builder.WriteString(fmt.Sprintf("\nDetected at: %s", time.Now().Format(time.RFC1123)))
+
+	message := builder.String()
+	log.Println(message)
+	title, message := c.formatNotification("DNS Drift Alert", message)
+	if err := c.send(ctx, title, message, providers.NotificationContext{}); err != nil {
+		log.Printf("❌ Error sending drift notification: %v ❌", err)
+	} else {
+		log.Println("✅ Drift notification sent successfully ✅")
+	}
+}
+
+// sendDNSSECNotification alerts on DNSSEC conditions found by
+// dns.CheckDNSSEC: expiring signatures, validation failures, and DNSKEY
+// rotations. It's composable with sendChangeDetectedNotification and
+// sendDriftNotification: all three can fire from the same PerformCheck run.
+func (c *Controller) sendDNSSECNotification(ctx context.Context, events []dns.DNSSECEvent) {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("🔏 DNSSEC ISSUE DETECTED for %s 🔏\n\n", c.config.Domain))
+	for i, event := range events {
+		builder.WriteString(fmt.Sprintf("%d. %s\n", i+1, event))
+	}
+	builder.WriteString(fmt.Sprintf("\nDetected at: %s", time.Now().Format(time.RFC1123)))
+
+	message := builder.String()
+	log.Println(message)
+	title, message := c.formatNotification("DNSSEC Alert", message)
+	if err := c.send(ctx, title, message, providers.NotificationContext{}); err != nil {
+		log.Printf("❌ Error sending DNSSEC notification: %v ❌", err)
+	} else {
+		log.Println("✅ DNSSEC notification sent successfully ✅")
+	}
+}
+
+func (c *Controller) sendErrorNotification(ctx context.Context, subject string, err error, failureType providers.FailureType) {
+	message := fmt.Sprintf("❌ DNS Monitor Error: %s\n\nError details: %v\n\nTime: %s ❌",
+		subject, err, time.Now().Format(time.RFC1123))
+	title, message := c.formatNotification("DNS Monitor Error", message)
+	if sendErr := c.send(ctx, title, message, providers.NotificationContext{FailureType: failureType}); sendErr != nil {
+		log.Printf("Failed to send error notification: %v", sendErr)
+	}
+}
+
+// formatNotification prepends the configured TitleTag to title and, when
+// SkipTitle is set, folds the title into the message body and returns an
+// empty title for providers that support untitled messages.
+func (c *Controller) formatNotification(title, message string) (string, string) {
+	if tag := c.config.NotificationConfig.TitleTag; tag != "" {
+		title = fmt.Sprintf("[%s] %s", tag, title)
+	}
+	if c.config.NotificationConfig.SkipTitle {
+		return "", fmt.Sprintf("%s\n\n%s", title, message)
+	}
+	return title, message
+}
+
+// send delivers a notification through the configured notifier and, when
+// metrics are attached, records the outcome under notifierLabel. When the
+// notifier implements providers.ContextualNotifier (e.g. the script
+// notifier), nctx is passed through so it can populate its DNS_MONITOR_*
+// environment variables beyond the rendered title/message; other notifiers
+// just get the plain SendNotification call.
+func (c *Controller) send(ctx context.Context, title, message string, nctx providers.NotificationContext) error {
+	if nctx.Domain == "" {
+		nctx.Domain = c.config.Domain
+	}
+
+	var err error
+	if cn, ok := c.notifier.(providers.ContextualNotifier); ok {
+		err = cn.SendNotificationWithContext(ctx, title, message, nctx)
+	} else {
+		err = c.notifier.SendNotification(ctx, title, message)
+	}
+	if c.metrics != nil {
+		c.metrics.RecordNotifierSend(c.notifierLabel(), err)
+	}
+	return err
+}
+
+// notifierLabel identifies the configured notifier for the
+// dnsmon_notifier_send_total metric: "urls" for the NotifierURLs fan-out
+// path, the legacy NotifierType otherwise.
+func (c *Controller) notifierLabel() string {
+	if len(c.config.NotificationConfig.NotifierURLs) > 0 {
+		return "urls"
+	}
+	if c.config.NotificationConfig.NotifierType != "" {
+		return c.config.NotificationConfig.NotifierType
+	}
+	return "unknown"
+}
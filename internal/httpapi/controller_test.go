@@ -0,0 +1,73 @@
+package httpapi
+
+import (
+	"context"
+	"dns-monitor/internal/common"
+	"testing"
+)
+
+func TestController_FormatNotification_TitleTag(t *testing.T) {
+	config := common.Config{
+		Domain:             "example.com",
+		NotificationConfig: common.NotificationConfig{TitleTag: "prod-example"},
+	}
+	controller := NewController(config, &stubNotifier{}, common.PreviousState{})
+
+	title, message := controller.formatNotification("DNS Change Alert", "body")
+	if title != "[prod-example] DNS Change Alert" {
+		t.Errorf("expected tagged title, got %q", title)
+	}
+	if message != "body" {
+		t.Errorf("expected message unchanged, got %q", message)
+	}
+}
+
+func TestController_FormatNotification_SkipTitle(t *testing.T) {
+	config := common.Config{
+		Domain:             "example.com",
+		NotificationConfig: common.NotificationConfig{SkipTitle: true},
+	}
+	controller := NewController(config, &stubNotifier{}, common.PreviousState{})
+
+	title, message := controller.formatNotification("DNS Change Alert", "body")
+	if title != "" {
+		t.Errorf("expected empty title, got %q", title)
+	}
+	if message != "DNS Change Alert\n\nbody" {
+		t.Errorf("expected title folded into message, got %q", message)
+	}
+}
+
+func TestController_SendStartupNotification(t *testing.T) {
+	notifier := &stubNotifier{}
+	controller := NewController(common.Config{Domain: "example.com"}, notifier, common.PreviousState{})
+
+	if err := controller.SendStartupNotification(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notifier.lastTitle != "DNS Monitor Started" {
+		t.Errorf("expected startup title, got %q", notifier.lastTitle)
+	}
+}
+
+func TestController_PerformCheck_RejectsConcurrentCheck(t *testing.T) {
+	controller := NewController(common.Config{Domain: "example.com"}, &stubNotifier{}, common.PreviousState{})
+	controller.checking.Store(true)
+
+	if _, err := controller.PerformCheck(context.Background()); err == nil {
+		t.Fatal("expected an error when a check is already in progress")
+	}
+}
+
+func TestController_RunHeartbeat_NoIntervalIsNoop(t *testing.T) {
+	notifier := &stubNotifier{}
+	controller := NewController(common.Config{Domain: "example.com"}, notifier, common.PreviousState{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	controller.RunHeartbeat(ctx)
+
+	if notifier.lastTitle != "" {
+		t.Errorf("expected no heartbeat sent without an interval, got title %q", notifier.lastTitle)
+	}
+}
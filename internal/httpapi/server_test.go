@@ -0,0 +1,75 @@
+package httpapi
+
+import (
+	"context"
+	"dns-monitor/internal/common"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubNotifier struct {
+	lastTitle   string
+	lastMessage string
+	err         error
+}
+
+func (s *stubNotifier) SendNotification(ctx context.Context, title, message string) error {
+	s.lastTitle = title
+	s.lastMessage = message
+	return s.err
+}
+
+func TestServer_HealthzAndStatus(t *testing.T) {
+	controller := NewController(common.Config{Domain: "example.com"}, &stubNotifier{}, common.PreviousState{
+		Records: []common.DNSRecord{{Type: "A", Name: "example.com.", Value: "192.0.2.1"}},
+	})
+	server := NewServer(":0", controller)
+
+	rec := httptest.NewRecorder()
+	server.handleHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	server.handleStatus(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var status Status
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	if status.Domain != "example.com" || status.RecordCount != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestServer_NotifyTest(t *testing.T) {
+	notifier := &stubNotifier{}
+	controller := NewController(common.Config{Domain: "example.com"}, notifier, common.PreviousState{})
+	server := NewServer(":0", controller)
+
+	rec := httptest.NewRecorder()
+	server.handleNotifyTest(rec, httptest.NewRequest(http.MethodPost, "/notify/test", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if notifier.lastTitle != "DNS Monitor Test Notification" {
+		t.Errorf("expected test notification title, got %q", notifier.lastTitle)
+	}
+}
+
+func TestServer_RejectsWrongMethod(t *testing.T) {
+	controller := NewController(common.Config{Domain: "example.com"}, &stubNotifier{}, common.PreviousState{})
+	server := NewServer(":0", controller)
+
+	rec := httptest.NewRecorder()
+	server.handleCheck(rec, httptest.NewRequest(http.MethodGet, "/check", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405, got %d", rec.Code)
+	}
+}
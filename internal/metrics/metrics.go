@@ -0,0 +1,127 @@
+// Package metrics exposes dns-monitor's internal counters and gauges as a
+// Prometheus registry, so operators can alert on check failures, noisy
+// zones, or a stalled monitor process.
+package metrics
+
+import (
+	"dns-monitor/internal/dns/diff"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus collectors dns-monitor exports. Each process
+// owns one Metrics instance bound to its own registry, so tests can spin up
+// an isolated instance instead of sharing the global default registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	ChecksTotal         *prometheus.CounterVec
+	RecordChangesTotal  *prometheus.CounterVec
+	QueryDuration       *prometheus.HistogramVec
+	RetryAttemptsTotal  *prometheus.CounterVec
+	NotifierSendTotal   *prometheus.CounterVec
+	LastSuccessfulCheck *prometheus.GaugeVec
+}
+
+// New creates a Metrics instance with its own registry and registers every
+// collector on it.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		ChecksTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dnsmon_checks_total",
+			Help: "Total number of DNS checks performed, by domain and result.",
+		}, []string{"domain", "result"}),
+		RecordChangesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dnsmon_record_changes_total",
+			Help: "Total number of DNS record changes detected, by domain, record type, and change.",
+		}, []string{"domain", "type", "change"}),
+		QueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "dnsmon_query_duration_seconds",
+			Help: "DNS query latency in seconds, by record type.",
+		}, []string{"record_type"}),
+		RetryAttemptsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dnsmon_retry_attempts_total",
+			Help: "Total number of DNS query retry attempts, by outcome.",
+		}, []string{"outcome"}),
+		NotifierSendTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dnsmon_notifier_send_total",
+			Help: "Total number of notification send attempts, by notifier and result.",
+		}, []string{"notifier", "result"}),
+		LastSuccessfulCheck: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "dnsmon_last_successful_check_timestamp_seconds",
+			Help: "Unix timestamp of the last successful DNS check, by domain.",
+		}, []string{"domain"}),
+	}
+
+	m.registry.MustRegister(
+		m.ChecksTotal,
+		m.RecordChangesTotal,
+		m.QueryDuration,
+		m.RetryAttemptsTotal,
+		m.NotifierSendTotal,
+		m.LastSuccessfulCheck,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler that serves m's registry in the
+// Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RecordCheck increments ChecksTotal for domain and, on success, advances
+// LastSuccessfulCheck to now.
+func (m *Metrics) RecordCheck(domain string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.ChecksTotal.WithLabelValues(domain, result).Inc()
+
+	if err == nil {
+		m.LastSuccessfulCheck.WithLabelValues(domain).Set(float64(time.Now().Unix()))
+	}
+}
+
+// RecordChanges increments RecordChangesTotal once per changed RRset
+// (CREATE, DELETE, or CHANGE) in result, labeled by domain, record type, and
+// verb. UNCHANGED RRsets aren't counted.
+func (m *Metrics) RecordChanges(domain string, result diff.Result) {
+	for _, hunk := range result.Changes() {
+		m.RecordChangesTotal.WithLabelValues(domain, hunk.Type, string(hunk.Verb)).Inc()
+	}
+}
+
+// RecordQueryDuration observes a DNS query's latency for recordType.
+func (m *Metrics) RecordQueryDuration(recordType string, seconds float64) {
+	m.QueryDuration.WithLabelValues(recordType).Observe(seconds)
+}
+
+// RetryHook returns a callback for common.RetryWithExponentialBackoffHook
+// that increments RetryAttemptsTotal by outcome. It's a plain func value
+// rather than a type common exports, so neither package needs to import
+// the other to wire metrics into retries.
+func (m *Metrics) RetryHook() func(success bool) {
+	return func(success bool) {
+		outcome := "success"
+		if !success {
+			outcome = "failure"
+		}
+		m.RetryAttemptsTotal.WithLabelValues(outcome).Inc()
+	}
+}
+
+// RecordNotifierSend increments NotifierSendTotal by notifier and result.
+func (m *Metrics) RecordNotifierSend(notifier string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.NotifierSendTotal.WithLabelValues(notifier, result).Inc()
+}
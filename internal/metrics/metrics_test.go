@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"dns-monitor/internal/dns/diff"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_RecordCheck(t *testing.T) {
+	m := New()
+
+	m.RecordCheck("example.com", nil)
+	m.RecordCheck("example.com", errors.New("boom"))
+
+	if got := testutil.ToFloat64(m.ChecksTotal.WithLabelValues("example.com", "success")); got != 1 {
+		t.Errorf("expected 1 success, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.ChecksTotal.WithLabelValues("example.com", "error")); got != 1 {
+		t.Errorf("expected 1 error, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.LastSuccessfulCheck.WithLabelValues("example.com")); got <= 0 {
+		t.Errorf("expected last successful check timestamp to be set, got %v", got)
+	}
+}
+
+func TestMetrics_RecordChanges(t *testing.T) {
+	m := New()
+
+	m.RecordChanges("example.com", diff.Result{
+		Hunks: []diff.Hunk{
+			{Type: "TXT", Name: "example.com.", Verb: diff.Create},
+			{Type: "MX", Name: "example.com.", Verb: diff.Change},
+			{Type: "CNAME", Name: "www.example.com.", Verb: diff.Delete},
+			{Type: "A", Name: "example.com.", Verb: diff.Unchanged},
+		},
+	})
+
+	cases := []struct {
+		recordType string
+		change     string
+	}{
+		{"TXT", "CREATE"},
+		{"MX", "CHANGE"},
+		{"CNAME", "DELETE"},
+	}
+	for _, tc := range cases {
+		if got := testutil.ToFloat64(m.RecordChangesTotal.WithLabelValues("example.com", tc.recordType, tc.change)); got != 1 {
+			t.Errorf("expected 1 change for %s/%s, got %v", tc.recordType, tc.change, got)
+		}
+	}
+
+	if got := testutil.CollectAndCount(m.RecordChangesTotal); got != len(cases) {
+		t.Errorf("expected %d tracked change series, got %d (UNCHANGED RRset should be skipped)", len(cases), got)
+	}
+}
+
+func TestMetrics_RetryHook(t *testing.T) {
+	m := New()
+	hook := m.RetryHook()
+
+	hook(true)
+	hook(false)
+	hook(false)
+
+	if got := testutil.ToFloat64(m.RetryAttemptsTotal.WithLabelValues("success")); got != 1 {
+		t.Errorf("expected 1 success, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.RetryAttemptsTotal.WithLabelValues("failure")); got != 2 {
+		t.Errorf("expected 2 failures, got %v", got)
+	}
+}
+
+func TestMetrics_RecordNotifierSend(t *testing.T) {
+	m := New()
+
+	m.RecordNotifierSend("pushover", nil)
+	m.RecordNotifierSend("pushover", errors.New("boom"))
+
+	if got := testutil.ToFloat64(m.NotifierSendTotal.WithLabelValues("pushover", "success")); got != 1 {
+		t.Errorf("expected 1 success, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.NotifierSendTotal.WithLabelValues("pushover", "error")); got != 1 {
+		t.Errorf("expected 1 error, got %v", got)
+	}
+}
@@ -3,35 +3,74 @@ package notification
 import (
 	c "dns-monitor/internal/common"
 	"dns-monitor/internal/notification/providers"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
 // Factory provides methods to create different notification services
 type Factory struct {
-	config *c.NotificationConfig
+	config     *c.NotificationConfig
+	httpClient *http.Client
 }
 
-// NewFactory creates a new notification factory with the given configuration
-func NewFactory(cfg *c.NotificationConfig) *Factory {
+// NewFactory creates a new notification factory for the given configuration.
+// The HTTP client is shared with notifiers (e.g. webhook) that need to make
+// their own requests.
+func NewFactory(cfg *c.Config) *Factory {
 	return &Factory{
-		config: cfg,
+		config:     &cfg.NotificationConfig,
+		httpClient: cfg.HTTPClient,
 	}
 }
 
-// CreateNotifier creates a notifier based on the configuration
+// CreateNotifier creates a notifier based on the configuration. NotifierURLs,
+// when set, is the primary path and produces a composite notifier that fans
+// out to every configured URL; the single-provider NotifierType switch is
+// kept as a legacy fallback.
 func (f *Factory) CreateNotifier() (providers.Notifier, error) {
+	if len(f.config.NotifierURLs) > 0 {
+		return f.createNotifierFromURLs()
+	}
+
 	switch strings.ToLower(f.config.NotifierType) {
 	case c.NotifierTypePushover:
 		return f.createPushoverService()
 	case c.NotifierTypeTelegram:
 		return f.createTelegramService()
+	case c.NotifierTypeWebhook:
+		return f.createWebhookService()
+	case c.NotifierTypeScript:
+		return f.createScriptService()
 	// Add more cases for other notifier types as needed
 	default:
 		return nil, fmt.Errorf("unsupported notifier type: %s", f.config.NotifierType)
 	}
 }
 
+// createNotifierFromURLs parses every configured notifier URL and combines
+// the resulting notifiers into a single MultiNotifier.
+func (f *Factory) createNotifierFromURLs() (providers.Notifier, error) {
+	notifiers := make([]providers.Notifier, 0, len(f.config.NotifierURLs))
+	var errs []error
+
+	for _, rawURL := range f.config.NotifierURLs {
+		notifier, err := providers.ParseURL(rawURL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		notifiers = append(notifiers, notifier)
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to build notifier(s) from NOTIFIER_URLS: %w", errors.Join(errs...))
+	}
+
+	return providers.NewMultiNotifier(notifiers...), nil
+}
+
 // createPushoverService creates a Pushover notification service from environment variables
 func (f *Factory) createPushoverService() (providers.Notifier, error) {
 	return providers.CreatePushoverService(f.config.PushoverUser, f.config.PushoverToken)
@@ -41,3 +80,22 @@ func (f *Factory) createPushoverService() (providers.Notifier, error) {
 func (f *Factory) createTelegramService() (providers.Notifier, error) {
 	return providers.CreateTelegramService(f.config.TelegramBotToken, f.config.TelegramChatIDs...)
 }
+
+// createWebhookService creates a generic HTTP webhook notifier from environment variables
+func (f *Factory) createWebhookService() (providers.Notifier, error) {
+	opts := providers.WebhookOptions{
+		Headers:       f.config.WebhookHeaders,
+		CAFile:        f.config.WebhookCAFile,
+		CertFile:      f.config.WebhookCertFile,
+		KeyFile:       f.config.WebhookKeyFile,
+		ServerName:    f.config.WebhookServerName,
+		SigningSecret: f.config.WebhookSigningSecret,
+	}
+	return providers.CreateWebhookService(f.config.WebhookURL, opts, f.httpClient)
+}
+
+// createScriptService creates a notifier that invokes a local script or
+// binary from environment variables
+func (f *Factory) createScriptService() (providers.Notifier, error) {
+	return providers.CreateScriptService(f.config.ScriptPath)
+}
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/nikoksr/notify"
+	"github.com/nikoksr/notify/service/discord"
+)
+
+func init() {
+	RegisterScheme("discord", parseDiscordURL)
+}
+
+// CreateDiscordService creates a Discord notification service that posts to
+// the given channel IDs using a bot token.
+func CreateDiscordService(botToken string, channelIDs ...string) (Notifier, error) {
+	discordService := discord.New()
+	if err := discordService.AuthenticateWithBotToken(botToken); err != nil {
+		return nil, fmt.Errorf("failed to authenticate with discord: %w", err)
+	}
+	discordService.AddReceivers(channelIDs...)
+
+	notifier := notify.New()
+	notifier.UseServices(discordService)
+
+	return NewService(notifier), nil
+}
+
+// parseDiscordURL builds a Discord notifier from a URL of the form
+// "discord://botToken@channelID".
+func parseDiscordURL(u *url.URL) (Notifier, error) {
+	botToken := u.User.Username()
+	channelID := u.Hostname()
+	if botToken == "" || channelID == "" {
+		return nil, fmt.Errorf("discord URL must be in the form discord://botToken@channelID")
+	}
+	return CreateDiscordService(botToken, channelID)
+}
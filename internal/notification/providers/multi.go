@@ -0,0 +1,52 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MultiNotifier fans a notification out across several notifiers concurrently.
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+// NewMultiNotifier creates a composite notifier that sends through every given notifier.
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+// SendNotification sends the notification through every wrapped notifier concurrently.
+// A failure in one notifier does not stop the others; all failures are joined
+// into a single error so callers can see exactly which providers failed.
+func (m *MultiNotifier) SendNotification(ctx context.Context, title, message string) error {
+	return m.SendNotificationWithContext(ctx, title, message, NotificationContext{})
+}
+
+// SendNotificationWithContext is like SendNotification, but passes nctx
+// through to any wrapped notifier that implements ContextualNotifier; the
+// rest just get the plain title/message.
+func (m *MultiNotifier) SendNotificationWithContext(ctx context.Context, title, message string, nctx NotificationContext) error {
+	errs := make([]error, len(m.notifiers))
+
+	var wg sync.WaitGroup
+	wg.Add(len(m.notifiers))
+	for i, notifier := range m.notifiers {
+		go func(i int, notifier Notifier) {
+			defer wg.Done()
+			var err error
+			if cn, ok := notifier.(ContextualNotifier); ok {
+				err = cn.SendNotificationWithContext(ctx, title, message, nctx)
+			} else {
+				err = notifier.SendNotification(ctx, title, message)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("notifier %d: %w", i, err)
+			}
+		}(i, notifier)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
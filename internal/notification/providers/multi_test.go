@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubNotifier struct {
+	err error
+}
+
+func (s *stubNotifier) SendNotification(ctx context.Context, title, message string) error {
+	return s.err
+}
+
+func TestMultiNotifier_SendNotification_AllSucceed(t *testing.T) {
+	m := NewMultiNotifier(&stubNotifier{}, &stubNotifier{})
+
+	if err := m.SendNotification(context.Background(), "title", "message"); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestMultiNotifier_SendNotification_PartialFailure(t *testing.T) {
+	failure := errors.New("boom")
+	m := NewMultiNotifier(&stubNotifier{}, &stubNotifier{err: failure})
+
+	err := m.SendNotification(context.Background(), "title", "message")
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if !errors.Is(err, failure) {
+		t.Errorf("expected joined error to wrap %v, got %v", failure, err)
+	}
+}
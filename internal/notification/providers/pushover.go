@@ -1,10 +1,17 @@
 package providers
 
 import (
+	"fmt"
+	"net/url"
+
 	"github.com/nikoksr/notify"
 	"github.com/nikoksr/notify/service/pushover"
 )
 
+func init() {
+	RegisterScheme("pushover", parsePushoverURL)
+}
+
 func CreatePushoverService(userKey, appToken string) (Notifier, error) {
 	notifier := notify.New()
 	pushoverService := pushover.New(appToken)
@@ -12,3 +19,14 @@ func CreatePushoverService(userKey, appToken string) (Notifier, error) {
 	notifier.UseServices(pushoverService)
 	return NewService(notifier), nil
 }
+
+// parsePushoverURL builds a Pushover notifier from a URL of the form
+// "pushover://appToken@userKey".
+func parsePushoverURL(u *url.URL) (Notifier, error) {
+	appToken := u.User.Username()
+	userKey := u.Hostname()
+	if appToken == "" || userKey == "" {
+		return nil, fmt.Errorf("pushover URL must be in the form pushover://appToken@userKey")
+	}
+	return CreatePushoverService(userKey, appToken)
+}
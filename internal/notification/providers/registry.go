@@ -0,0 +1,34 @@
+package providers
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// URLParser builds a Notifier from a single parsed notifier URL, e.g.
+// "pushover://token@userKey/?priority=1".
+type URLParser func(u *url.URL) (Notifier, error)
+
+var urlParsers = map[string]URLParser{}
+
+// RegisterScheme registers a URLParser for a notifier URL scheme. Providers
+// call this from an init() func so new schemes plug in without the factory
+// needing to know about them.
+func RegisterScheme(scheme string, parser URLParser) {
+	urlParsers[scheme] = parser
+}
+
+// ParseURL builds a Notifier from a single shoutrrr-style notifier URL.
+func ParseURL(rawURL string) (Notifier, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifier URL %q: %w", rawURL, err)
+	}
+
+	parser, ok := urlParsers[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported notifier scheme: %s", u.Scheme)
+	}
+
+	return parser(u)
+}
@@ -0,0 +1,31 @@
+package providers
+
+import "testing"
+
+func TestParseURL_UnsupportedScheme(t *testing.T) {
+	_, err := ParseURL("carrier-pigeon://nope")
+	if err == nil {
+		t.Fatal("expected an error for an unsupported scheme, got nil")
+	}
+}
+
+func TestParseURL_InvalidURL(t *testing.T) {
+	_, err := ParseURL("://not-a-url")
+	if err == nil {
+		t.Fatal("expected an error for an invalid URL, got nil")
+	}
+}
+
+func TestParseURL_PushoverMissingUserKey(t *testing.T) {
+	_, err := ParseURL("pushover://token@")
+	if err == nil {
+		t.Fatal("expected an error when the userKey is missing, got nil")
+	}
+}
+
+func TestParseURL_TelegramMissingChats(t *testing.T) {
+	_, err := ParseURL("telegram://botToken@telegram")
+	if err == nil {
+		t.Fatal("expected an error when the chats query parameter is missing, got nil")
+	}
+}
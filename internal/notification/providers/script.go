@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"dns-monitor/internal/common"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const (
+	scriptMaxRetries   = 5
+	scriptInitialDelay = 500 * time.Millisecond
+	scriptTimeout      = 30 * time.Second
+)
+
+type scriptNotifier struct {
+	path string
+}
+
+// CreateScriptService creates a notifier that invokes a local script or
+// binary for every notification, passing the title and message both as
+// arguments and as DNS_MONITOR_* environment variables. This unlocks
+// arbitrary integrations (webhook signing proxies, PagerDuty CLIs, on-box
+// mitigations) without a dedicated provider for each.
+//
+// It also implements ContextualNotifier, so callers that have a
+// NotificationContext available additionally get DNS_MONITOR_DOMAIN,
+// DNS_MONITOR_FAILURE_TYPE, and DNS_MONITOR_CHANGES_JSON.
+func CreateScriptService(path string) (Notifier, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("script notifier path is not accessible: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("script notifier path is a directory: %s", path)
+	}
+	if info.Mode()&0111 == 0 {
+		return nil, fmt.Errorf("script notifier path is not executable: %s", path)
+	}
+
+	return &scriptNotifier{path: path}, nil
+}
+
+// SendNotification runs the configured script with title and message,
+// retrying on a non-zero exit code.
+func (s *scriptNotifier) SendNotification(ctx context.Context, title, message string) error {
+	return s.SendNotificationWithContext(ctx, title, message, NotificationContext{})
+}
+
+// SendNotificationWithContext is like SendNotification but additionally
+// populates DNS_MONITOR_DOMAIN, DNS_MONITOR_FAILURE_TYPE, and
+// DNS_MONITOR_CHANGES_JSON (a JSON array) from nctx, so a script can branch
+// on what happened instead of scraping the rendered message.
+func (s *scriptNotifier) SendNotificationWithContext(ctx context.Context, title, message string, nctx NotificationContext) error {
+	detectedAt := time.Now().Format(time.RFC3339)
+
+	changesJSON, err := json.Marshal(nctx.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes for script notifier: %w", err)
+	}
+
+	operation := func() error {
+		runCtx, cancel := context.WithTimeout(ctx, scriptTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(runCtx, s.path, title, message)
+		cmd.Env = append(os.Environ(),
+			"DNS_MONITOR_TITLE="+title,
+			"DNS_MONITOR_MESSAGE="+message,
+			"DNS_MONITOR_DETECTED_AT="+detectedAt,
+			"DNS_MONITOR_DOMAIN="+nctx.Domain,
+			"DNS_MONITOR_FAILURE_TYPE="+string(nctx.FailureType),
+			"DNS_MONITOR_CHANGES_JSON="+string(changesJSON),
+		)
+
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("script notifier %s failed: %w (output: %s)", s.path, err, output.String())
+		}
+		return nil
+	}
+
+	return common.RetryWithExponentialBackoff(ctx, scriptMaxRetries, scriptInitialDelay, operation)
+}
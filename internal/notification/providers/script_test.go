@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sh")
+	if err := os.WriteFile(path, []byte(body), 0o755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
+
+func TestCreateScriptService_RunsWithEnvAndArgs(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "out.txt")
+	script := writeScript(t, "#!/bin/sh\necho \"$1|$2|$DNS_MONITOR_TITLE|$DNS_MONITOR_MESSAGE\" > "+outPath+"\n")
+
+	notifier, err := CreateScriptService(script)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := notifier.SendNotification(context.Background(), "DNS Change Alert", "example.com changed"); err != nil {
+		t.Fatalf("unexpected send error: %v", err)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read script output: %v", err)
+	}
+	want := "DNS Change Alert|example.com changed|DNS Change Alert|example.com changed\n"
+	if string(out) != want {
+		t.Errorf("expected script output %q, got %q", want, string(out))
+	}
+}
+
+func TestCreateScriptService_NonZeroExitIsError(t *testing.T) {
+	script := writeScript(t, "#!/bin/sh\nexit 1\n")
+
+	notifier, err := CreateScriptService(script)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A context that's already past its deadline lets the retry loop fail
+	// fast instead of running through every exponential backoff wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if err := notifier.SendNotification(ctx, "title", "message"); err == nil {
+		t.Fatal("expected an error for a non-zero exit code, got nil")
+	}
+}
+
+func TestCreateScriptService_NotExecutable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	if _, err := CreateScriptService(path); err == nil {
+		t.Fatal("expected an error for a non-executable script, got nil")
+	}
+}
+
+func TestCreateScriptService_MissingPath(t *testing.T) {
+	if _, err := CreateScriptService("/nonexistent/notify.sh"); err == nil {
+		t.Fatal("expected an error for a missing script, got nil")
+	}
+}
@@ -19,6 +19,36 @@ type Notifier interface {
 	SendNotification(ctx context.Context, title, message string) error
 }
 
+// FailureType categorizes what triggered a notification, for notifiers that
+// branch on it (see ContextualNotifier).
+type FailureType string
+
+const (
+	FailureTypeDNSChange  FailureType = "DNSChange"
+	FailureTypeFetchError FailureType = "FetchError"
+	FailureTypeStateError FailureType = "StateError"
+	FailureTypeTest       FailureType = "Test"
+)
+
+// NotificationContext carries structured metadata about a notification
+// alongside the rendered title/message every Notifier accepts. Changes holds
+// the raw list of changed items (e.g. one entry per diff.Hunk), not the
+// human-readable message built from them, so a notifier can re-serialize
+// them on its own terms.
+type NotificationContext struct {
+	Domain      string
+	FailureType FailureType
+	Changes     []string
+}
+
+// ContextualNotifier is implemented by notifiers that can act on
+// NotificationContext in addition to the plain title/message every Notifier
+// accepts. Callers should type-assert for it and fall back to
+// SendNotification when a notifier doesn't implement it.
+type ContextualNotifier interface {
+	SendNotificationWithContext(ctx context.Context, title, message string, nctx NotificationContext) error
+}
+
 // Service represents a notification service
 type Service struct {
 	notifier *notify.Notify
@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nikoksr/notify"
+	"github.com/nikoksr/notify/service/slack"
+)
+
+func init() {
+	RegisterScheme("slack", parseSlackURL)
+}
+
+// CreateSlackService creates a Slack notification service that posts to the
+// given channel IDs using a bot token.
+func CreateSlackService(botToken string, channelIDs ...string) (Notifier, error) {
+	slackService := slack.New(botToken)
+	slackService.AddReceivers(channelIDs...)
+
+	notifier := notify.New()
+	notifier.UseServices(slackService)
+
+	return NewService(notifier), nil
+}
+
+// parseSlackURL builds a Slack notifier from a URL of the form
+// "slack://botToken@channelA,channelB".
+func parseSlackURL(u *url.URL) (Notifier, error) {
+	botToken := u.User.Username()
+	if botToken == "" {
+		return nil, fmt.Errorf("slack URL must be in the form slack://botToken@channelA,channelB")
+	}
+
+	channels := strings.Split(u.Hostname(), ",")
+	if len(channels) == 0 || channels[0] == "" {
+		return nil, fmt.Errorf("slack URL must specify at least one channel")
+	}
+
+	return CreateSlackService(botToken, channels...)
+}
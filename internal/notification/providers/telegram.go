@@ -1,10 +1,19 @@
 package providers
 
 import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"github.com/nikoksr/notify"
 	"github.com/nikoksr/notify/service/telegram"
 )
 
+func init() {
+	RegisterScheme("telegram", parseTelegramURL)
+}
+
 // CreateTelegramService creates a Telegram notification service
 func CreateTelegramService(botToken string, chatIDs ...int64) (Notifier, error) {
 	telegramService, err := telegram.New(botToken)
@@ -22,3 +31,40 @@ func CreateTelegramService(botToken string, chatIDs ...int64) (Notifier, error)
 
 	return NewService(notifier), nil
 }
+
+// parseTelegramURL builds a Telegram notifier from a URL of the form
+// "telegram://botToken@telegram?chats=123,456".
+func parseTelegramURL(u *url.URL) (Notifier, error) {
+	botToken := u.User.Username()
+	if botToken == "" {
+		return nil, fmt.Errorf("telegram URL must be in the form telegram://botToken@telegram?chats=...")
+	}
+
+	chatIDs, err := parseChatIDs(u.Query().Get("chats"))
+	if err != nil {
+		return nil, err
+	}
+	if len(chatIDs) == 0 {
+		return nil, fmt.Errorf("telegram URL must specify at least one chat in the chats query parameter")
+	}
+
+	return CreateTelegramService(botToken, chatIDs...)
+}
+
+func parseChatIDs(raw string) ([]int64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	chatIDs := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		chatID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid telegram chat id %q: %w", part, err)
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+
+	return chatIDs, nil
+}
@@ -0,0 +1,238 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"dns-monitor/internal/common"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterScheme("webhook", parseWebhookURL)
+}
+
+const (
+	webhookMaxRetries   = 5
+	webhookInitialDelay = 500 * time.Millisecond
+
+	// WebhookSignatureHeader carries the HMAC-SHA256 signature of the raw
+	// request body, hex-encoded, so receivers can verify the request
+	// actually came from this dns-monitor instance. Only set when a signing
+	// secret is configured.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookOptions configures the generic HTTP webhook notifier, including
+// optional mutual TLS and payload signing.
+type WebhookOptions struct {
+	Headers    map[string]string
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+	// SigningSecret, when set, signs every request body with HMAC-SHA256 and
+	// carries the hex-encoded signature in WebhookSignatureHeader.
+	SigningSecret string
+}
+
+type webhookPayload struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	// Changes holds message split into its individual non-empty lines, so a
+	// receiver can render the hunks sendChangeDetectedNotification reported
+	// as a list instead of parsing the combined message body itself.
+	Changes    []string  `json:"changes"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+type webhookNotifier struct {
+	endpoint      string
+	headers       map[string]string
+	signingSecret string
+	client        *http.Client
+}
+
+// CreateWebhookService creates a generic HTTP webhook notifier that POSTs a
+// JSON payload to endpoint, optionally authenticating with a client
+// certificate for mutual TLS and signing the payload for the receiver to
+// verify.
+func CreateWebhookService(endpoint string, opts WebhookOptions, httpClient *http.Client) (Notifier, error) {
+	client, err := webhookHTTPClient(httpClient, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookNotifier{
+		endpoint:      endpoint,
+		headers:       opts.Headers,
+		signingSecret: opts.SigningSecret,
+		client:        client,
+	}, nil
+}
+
+// SendNotification POSTs the notification as JSON, retrying transient failures.
+func (w *webhookNotifier) SendNotification(ctx context.Context, title, message string) error {
+	body, err := json.Marshal(webhookPayload{
+		Title:      title,
+		Message:    message,
+		Changes:    messageLines(message),
+		DetectedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	operation := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range w.headers {
+			req.Header.Set(key, value)
+		}
+		if w.signingSecret != "" {
+			req.Header.Set(WebhookSignatureHeader, "sha256="+signBody(body, w.signingSecret))
+		}
+
+		resp, err := w.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("webhook request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("non-2xx response from webhook: %d", resp.StatusCode)
+		}
+		return nil
+	}
+
+	return common.RetryWithExponentialBackoff(ctx, webhookMaxRetries, webhookInitialDelay, operation)
+}
+
+// signBody returns the hex-encoded HMAC-SHA256 of body keyed by secret.
+func signBody(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// messageLines splits message into its non-empty, trimmed lines.
+func messageLines(message string) []string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			lines = append(lines, trimmed)
+		}
+	}
+	return lines
+}
+
+// parseWebhookURL builds a webhook notifier from a URL of the form
+// "webhook://host/path?headers=K=V,K2=V2&signing_secret=...&insecure=true".
+// The target is addressed over https unless insecure=true is set. headers is
+// a comma-separated list of Key=Value pairs, matching WEBHOOK_HEADERS.
+// ca_file, cert_file, key_file, and server_name configure mutual TLS, as for
+// the WEBHOOK_CA_FILE/WEBHOOK_CERT_FILE/WEBHOOK_KEY_FILE/WEBHOOK_SERVER_NAME
+// environment variables.
+func parseWebhookURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("webhook URL must be in the form webhook://host/path")
+	}
+
+	query := u.Query()
+
+	scheme := "https"
+	if query.Get("insecure") == "true" {
+		scheme = "http"
+	}
+	endpoint := scheme + "://" + u.Host + u.Path
+
+	opts := WebhookOptions{
+		Headers:       parseHeaderList(query.Get("headers")),
+		CAFile:        query.Get("ca_file"),
+		CertFile:      query.Get("cert_file"),
+		KeyFile:       query.Get("key_file"),
+		ServerName:    query.Get("server_name"),
+		SigningSecret: query.Get("signing_secret"),
+	}
+
+	return CreateWebhookService(endpoint, opts, http.DefaultClient)
+}
+
+// parseHeaderList parses a comma-separated "Key=Value,Key2=Value2" string,
+// the same format as the WEBHOOK_HEADERS environment variable.
+func parseHeaderList(raw string) map[string]string {
+	headers := make(map[string]string)
+	if raw == "" {
+		return headers
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found || key == "" {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// webhookHTTPClient clones base's transport and installs a TLS config built
+// from opts, so mTLS settings apply only to webhook requests and not to the
+// shared HTTP client used elsewhere.
+func webhookHTTPClient(base *http.Client, opts WebhookOptions) (*http.Client, error) {
+	if opts.CAFile == "" && opts.CertFile == "" && opts.KeyFile == "" && opts.ServerName == "" {
+		return base, nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if opts.CAFile != "" {
+		caCert, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse webhook CA file: %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load webhook client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.ServerName != "" {
+		tlsConfig.ServerName = opts.ServerName
+	}
+
+	transport := baseTransport(base).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	client := *base
+	client.Transport = transport
+	return &client, nil
+}
+
+func baseTransport(client *http.Client) *http.Transport {
+	if transport, ok := client.Transport.(*http.Transport); ok && transport != nil {
+		return transport
+	}
+	return http.DefaultTransport.(*http.Transport)
+}
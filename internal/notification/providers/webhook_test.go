@@ -0,0 +1,176 @@
+package providers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCreateWebhookService_SendsJSONPayload(t *testing.T) {
+	var received webhookPayload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected Content-Type application/json, got %q", r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("expected custom header to be forwarded, got %q", r.Header.Get("X-Api-Key"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := CreateWebhookService(server.URL, WebhookOptions{
+		Headers: map[string]string{"X-Api-Key": "secret"},
+	}, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := notifier.SendNotification(context.Background(), "DNS Change Alert", "example.com changed"); err != nil {
+		t.Fatalf("unexpected send error: %v", err)
+	}
+
+	if received.Title != "DNS Change Alert" || received.Message != "example.com changed" {
+		t.Errorf("unexpected payload received: %+v", received)
+	}
+}
+
+func TestCreateWebhookService_InvalidCAFile(t *testing.T) {
+	_, err := CreateWebhookService("https://example.com/webhook", WebhookOptions{
+		CAFile: "/nonexistent/ca.pem",
+	}, http.DefaultClient)
+	if err == nil {
+		t.Fatal("expected an error for a missing CA file, got nil")
+	}
+}
+
+func TestCreateWebhookService_SignsPayloadAndSplitsChanges(t *testing.T) {
+	var body []byte
+	var signature string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(WebhookSignatureHeader)
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := CreateWebhookService(server.URL, WebhookOptions{
+		SigningSecret: "shh",
+	}, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	message := "A example.com -> 192.0.2.1\nTXT example.com -> v=spf1 ~all"
+	if err := notifier.SendNotification(context.Background(), "DNS Change Alert", message); err != nil {
+		t.Fatalf("unexpected send error: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if signature != want {
+		t.Errorf("expected signature %q, got %q", want, signature)
+	}
+
+	var received webhookPayload
+	if err := json.Unmarshal(body, &received); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	wantChanges := []string{"A example.com -> 192.0.2.1", "TXT example.com -> v=spf1 ~all"}
+	if len(received.Changes) != len(wantChanges) || received.Changes[0] != wantChanges[0] || received.Changes[1] != wantChanges[1] {
+		t.Errorf("unexpected changes: %v", received.Changes)
+	}
+}
+
+func TestCreateWebhookService_NoSigningSecretOmitsHeader(t *testing.T) {
+	var sawHeader bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, sawHeader = r.Header[WebhookSignatureHeader]
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := CreateWebhookService(server.URL, WebhookOptions{}, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := notifier.SendNotification(context.Background(), "title", "message"); err != nil {
+		t.Fatalf("unexpected send error: %v", err)
+	}
+	if sawHeader {
+		t.Errorf("expected no signature header when SigningSecret is unset")
+	}
+}
+
+func TestParseWebhookURL(t *testing.T) {
+	u, err := url.Parse("webhook://example.com/hook?headers=X-Api-Key=secret&signing_secret=shh")
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	notifier, err := parseWebhookURL(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wn, ok := notifier.(*webhookNotifier)
+	if !ok {
+		t.Fatalf("expected *webhookNotifier, got %T", notifier)
+	}
+	if wn.endpoint != "https://example.com/hook" {
+		t.Errorf("expected https endpoint by default, got %q", wn.endpoint)
+	}
+	if wn.headers["X-Api-Key"] != "secret" {
+		t.Errorf("expected header to be parsed, got %v", wn.headers)
+	}
+	if wn.signingSecret != "shh" {
+		t.Errorf("expected signing secret to be parsed, got %q", wn.signingSecret)
+	}
+}
+
+func TestParseWebhookURL_Insecure(t *testing.T) {
+	u, err := url.Parse("webhook://example.com/hook?insecure=true")
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	notifier, err := parseWebhookURL(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wn := notifier.(*webhookNotifier)
+	if !strings.HasPrefix(wn.endpoint, "http://") {
+		t.Errorf("expected http endpoint when insecure=true, got %q", wn.endpoint)
+	}
+}
+
+func TestParseWebhookURL_RequiresHost(t *testing.T) {
+	u, err := url.Parse("webhook://")
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	if _, err := parseWebhookURL(u); err == nil {
+		t.Fatal("expected an error for a missing host, got nil")
+	}
+}
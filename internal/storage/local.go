@@ -7,10 +7,11 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-func LoadPreviousState() (common.PreviousState, error) {
-	stateFile, err := getStateFileLocation()
+func LoadPreviousState(domain string) (common.PreviousState, error) {
+	stateFile, err := getStateFileLocation(domain)
 	if err != nil {
 		log.Printf("Error retrieving the state file path: %v", err)
 		return common.PreviousState{}, fmt.Errorf("failed to retrieve the state file path: %w", err)
@@ -36,14 +37,14 @@ func LoadPreviousState() (common.PreviousState, error) {
 	return state, nil
 }
 
-func SavePreviousState(state common.PreviousState) error {
+func SavePreviousState(state common.PreviousState, domain string) error {
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		log.Printf("Error serializing state: %v", err)
 		return fmt.Errorf("failed to serialize state: %w", err)
 	}
 
-	stateFile, err := getStateFileLocation()
+	stateFile, err := getStateFileLocation(domain)
 	if err != nil {
 		log.Printf("Error retrieving the state file path: %v", err)
 		return fmt.Errorf("failed to retrieve the state file path: %w", err)
@@ -60,8 +61,10 @@ func SavePreviousState(state common.PreviousState) error {
 	return nil
 }
 
-func getStateFileLocation() (string, error) {
-	const fileName = "/dns_state.json"
+// getStateFileLocation returns the state file path for the given domain, so
+// each monitored domain keeps its own snapshot.
+func getStateFileLocation(domain string) (string, error) {
+	fileName := fmt.Sprintf("/dns_state_%s.json", sanitizeDomainForFilename(domain))
 	workingDir, err := os.Getwd()
 	if err != nil {
 		log.Fatalf("Error getting working directory: %v", err)
@@ -76,3 +79,10 @@ func getStateFileLocation() (string, error) {
 	}
 	return dir + fileName, nil
 }
+
+// sanitizeDomainForFilename replaces characters that aren't filesystem-safe
+// in a domain name so it can be used as a state file name.
+func sanitizeDomainForFilename(domain string) string {
+	replacer := strings.NewReplacer(".", "_", "/", "_", "\\", "_")
+	return replacer.Replace(domain)
+}